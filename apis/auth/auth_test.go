@@ -15,7 +15,6 @@
 package auth
 
 import (
-	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -31,13 +30,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestCode2Session(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiCode2Session, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiCode2Session)
 
 	type args struct {
 		ctx *microapp.MicroApp
@@ -50,18 +43,17 @@ func TestCode2Session(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp, params: url.Values{}}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, params: url.Values{}}, wantResp: []byte(mock.Response), wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := Code2Session(tt.args.ctx, tt.args.params)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Code2Session() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("Code2Session() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
@@ -0,0 +1,80 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/fastwego/microapp"
+)
+
+// Code2SessionRequest code2Session 请求参数
+type Code2SessionRequest struct {
+	Code          string `json:"-"` // login 接口返回的登录凭证
+	AnonymousCode string `json:"-"` // login 接口返回的匿名登录凭证
+}
+
+// Validate 校验必填参数
+func (req Code2SessionRequest) Validate() error {
+	return nil
+}
+
+// Params query 参数
+func (req Code2SessionRequest) Params() url.Values {
+	params := url.Values{}
+	if req.Code != "" {
+		params.Add("code", req.Code)
+	}
+	if req.AnonymousCode != "" {
+		params.Add("anonymous_code", req.AnonymousCode)
+	}
+	return params
+}
+
+// Code2SessionResponse code2Session 响应
+type Code2SessionResponse struct {
+	Errcode         int64  `json:"errcode"`
+	Errmsg          string `json:"errmsg"`
+	SessionKey      string `json:"session_key"`      // 会话密钥
+	Openid          string `json:"openid"`           // 用户在当前小程序的 ID
+	AnonymousOpenid string `json:"anonymous_openid"` // 匿名用户在当前小程序的 ID
+	Unionid         string `json:"unionid"`          // 用户在小程序平台的唯一标识符
+}
+
+/*
+code2Session
+
+使用结构体 作为请求参数和响应 的 Code2Session，调用前校验必填参数，自动填充 appid/secret
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/log-in/code-2-session
+
+GET https://developer.toutiao.com/api/apps/jscode2session
+*/
+func Code2SessionTyped(ctx *microapp.MicroApp, req Code2SessionRequest) (resp Code2SessionResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	params := req.Params()
+
+	raw, err := Code2Session(ctx, params)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
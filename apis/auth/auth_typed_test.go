@@ -0,0 +1,47 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestCode2SessionTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     Code2SessionRequest
+		wantErr bool
+	}{
+		{name: "case1", req: Code2SessionRequest{Code: "test"}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Code2SessionTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Code2SessionTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.SessionKey != "SESSION_KEY" {
+				t.Errorf("resp.SessionKey = %v, want %v", resp.SessionKey, "SESSION_KEY")
+			}
+		})
+	}
+
+	env.Capture.AssertQuery(t, apiCode2Session, "code", "test")
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package code_management
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fastwego/microapp"
+)
+
+var ErrorNoAudit = errors.New("no version in audit")
+
+// DefaultAuditInterval WaitAudit 的 interval 不大于 0 时 使用的 查询间隔
+const DefaultAuditInterval = time.Minute
+
+/*
+WaitAudit 每隔 interval 查询一次 审核版本，直到 审核通过 或 不通过；interval 不大于 0 时 使用 DefaultAuditInterval
+
+没有 审核版本 时 返回 ErrorNoAudit；c 取消 或 超时 时（包括 查询进行中）返回 最后一次查询到的 审核版本 和 c.Err()
+
+	c, cancel := context.WithTimeout(context.Background(), 24*time.Hour)
+	defer cancel()
+	audit, err := code_management.WaitAudit(c, ctx, 10*time.Minute)
+*/
+func WaitAudit(c context.Context, ctx *microapp.MicroApp, interval time.Duration) (audit AuditVersion, err error) {
+	if interval <= 0 {
+		interval = DefaultAuditInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var versions VersionInfo
+		if err = doContext(c, ctx, http.MethodGet, apiVersions, nil, &versions); err != nil {
+			if c.Err() != nil {
+				err = c.Err()
+			}
+			return audit, err
+		}
+		if versions.Audit == nil {
+			return audit, ErrorNoAudit
+		}
+
+		audit = *versions.Audit
+		if audit.Status != AuditStatusAuditing {
+			return audit, nil
+		}
+
+		select {
+		case <-c.Done():
+			return audit, c.Err()
+		case <-ticker.C:
+		}
+	}
+}
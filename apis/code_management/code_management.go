@@ -0,0 +1,159 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package code_management 第三方平台 代授权小程序 管理代码
+
+上传代码模板 生成测试版本 => 提审 => 审核通过后 发布，发布后 可回退到 上一个版本
+
+接口 使用 component_appid 和 authorizer_access_token 鉴权，ctx 需要通过 component.NewMicroApp 创建；
+接口返回 errno 不为 0 时 返回 *APIError
+
+	ctx := platform.NewMicroApp(authorizerAppId)
+	err := code_management.Upload(ctx, code_management.UploadRequest{TemplateId: 1, UserDesc: "init", UserVersion: "1.0.0", ExtJson: `{"extEnable":true}`})
+	err = code_management.Audit(ctx, code_management.AuditRequest{HostNames: []string{"douyin"}})
+	audit, err := code_management.WaitAudit(context.Background(), ctx, time.Minute)
+	if audit.Status == code_management.AuditStatusPass {
+		err = code_management.Release(ctx)
+	}
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/upload-code
+*/
+package code_management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/fastwego/microapp"
+)
+
+const (
+	apiUpload   = "/openapi/v1/microapp/package/upload"
+	apiAudit    = "/openapi/v1/microapp/package/audit"
+	apiVersions = "/openapi/v1/microapp/package/versions"
+	apiRelease  = "/openapi/v1/microapp/package/release"
+	apiRollback = "/openapi/v1/microapp/package/rollback"
+)
+
+// ErrnoAccessTokenExpire authorizer_access_token 无效或过期 的错误码 errno
+const ErrnoAccessTokenExpire = 40002
+
+var ErrorNoComponentAppId = errors.New("component_appid not configured, create ctx with component.NewMicroApp")
+
+/*
+APIError 第三方平台 接口错误，errno 不为 0
+*/
+type APIError struct {
+	Errno   int64  `json:"errno"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("errno %d: %s", e.Errno, e.Message)
+}
+
+// response 接口响应，data 字段 按接口 解析
+type response struct {
+	APIError
+	Data json.RawMessage `json:"data"`
+}
+
+/*
+do 发送请求，data 不为空时 解析 响应中的 data 字段
+
+请求 发往 ctx.Client 的 api 服务器，component.NewMicroApp 创建的实例 为 第三方平台 api 服务器；
+query 参数 自动填充 component_appid 和 authorizer_access_token，authorizer_access_token 过期（401 或 errno 为 ErrnoAccessTokenExpire）时 刷新 并重试
+*/
+func do(ctx *microapp.MicroApp, method string, uri string, req interface{}, data interface{}) (err error) {
+	return doContext(context.Background(), ctx, method, uri, req, data)
+}
+
+// doContext 同 do，请求 绑定 c，c 取消时 进行中的请求 立即返回
+func doContext(c context.Context, ctx *microapp.MicroApp, method string, uri string, req interface{}, data interface{}) (err error) {
+	if ctx.Config.ComponentAppId == "" {
+		return ErrorNoComponentAppId
+	}
+
+	var payload []byte
+	if method != http.MethodGet && req != nil {
+		if payload, err = json.Marshal(req); err != nil {
+			return
+		}
+	}
+
+	resp, err := send(c, ctx, method, uri, payload)
+	if err != nil {
+		return
+	}
+
+	// errno 过期 只有 第三方平台 接口 返回，在这里 处理，401 由 Client 刷新 并重试
+	if resp.Errno == ErrnoAccessTokenExpire {
+		if err = ctx.NoticeAccessTokenExpireHandler(ctx); err != nil {
+			return
+		}
+		if resp, err = send(c, ctx, method, uri, payload); err != nil {
+			return
+		}
+		if resp.Errno == ErrnoAccessTokenExpire {
+			return microapp.ErrorAccessTokenExpire
+		}
+	}
+
+	if resp.Errno != 0 {
+		return &APIError{Errno: resp.Errno, Message: resp.Message}
+	}
+	if data != nil && len(resp.Data) > 0 {
+		err = json.Unmarshal(resp.Data, data)
+	}
+	return
+}
+
+// send 使用 当前的 authorizer_access_token 发送一次请求
+func send(c context.Context, ctx *microapp.MicroApp, method string, uri string, payload []byte) (resp response, err error) {
+	accessToken, err := ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+	params := url.Values{}
+	params.Add("component_appid", ctx.Config.ComponentAppId)
+	params.Add("authorizer_access_token", accessToken)
+
+	var body io.Reader
+	if method != http.MethodGet {
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := ctx.Client.NewRequest(method, uri+"?"+params.Encode(), body)
+	if err != nil {
+		return
+	}
+	request = request.WithContext(c)
+	if body != nil {
+		request.Header.Add("Content-Type", "application/json;charset=utf-8")
+	}
+
+	raw, err := ctx.Client.HTTPDo(request)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(raw, &resp)
+	return
+}
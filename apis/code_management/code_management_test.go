@@ -0,0 +1,230 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package code_management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func newEnv(t *testing.T) *test.Env {
+	return test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET", ComponentAppId: "COMPONENT_APPID"})
+}
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	// authorizer_access_token 过期后 刷新 并重试
+	env.Faults.Inject(apiUpload, test.Fault{Status: http.StatusUnauthorized})
+
+	err := Upload(env.MicroApp, UploadRequest{TemplateId: 1, UserDesc: "init", UserVersion: "1.0.0", ExtJson: `{"extEnable":true}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := env.Faults.Calls(apiUpload); calls != 2 {
+		t.Errorf("calls = %d, want retry after 401", calls)
+	}
+	env.Capture.AssertQuery(t, apiUpload, "component_appid", "COMPONENT_APPID")
+	env.Capture.AssertQuery(t, apiUpload, "authorizer_access_token", "ACCESS_TOKEN")
+	env.Capture.AssertJSONField(t, apiUpload, "template_id", float64(1))
+	env.Capture.AssertJSONField(t, apiUpload, "ext_json", `{"extEnable":true}`)
+
+	if err = Upload(env.MicroApp, UploadRequest{TemplateId: 1, UserDesc: "init", UserVersion: "1.0.0", ExtJson: "extEnable"}); err == nil {
+		t.Error("invalid ext_json should fail validation")
+	}
+}
+
+func TestAccessTokenExpire(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	var calls, notices int32
+	notice := env.MicroApp.NoticeAccessTokenExpireHandler
+	env.MicroApp.NoticeAccessTokenExpireHandler = func(ctx *microapp.MicroApp) error {
+		atomic.AddInt32(&notices, 1)
+		return notice(ctx)
+	}
+	env.Mux.HandleFunc(apiRelease, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = fmt.Fprintf(w, `{"errno":%d,"message":"invalid authorizer_access_token"}`, ErrnoAccessTokenExpire)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errno":0,"message":"success"}`))
+	})
+
+	// errno 过期 与 401 一样 刷新 authorizer_access_token 并重试
+	if err := Release(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || notices != 1 {
+		t.Errorf("errno: calls = %d, notices = %d, want refresh and retry", calls, notices)
+	}
+
+	env.Faults.Inject(apiRollback, test.Fault{Status: http.StatusUnauthorized})
+	if err := Rollback(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+	if got := env.Faults.Calls(apiRollback); got != 2 || notices != 2 {
+		t.Errorf("401: calls = %d, notices = %d, want refresh and retry", got, notices)
+	}
+
+	// 重试后 仍然过期
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"errno":%d,"message":"invalid authorizer_access_token"}`, ErrnoAccessTokenExpire)
+	})
+	if _, err := Versions(env.MicroApp); err != microapp.ErrorAccessTokenExpire {
+		t.Errorf("Versions() err = %v, want %v", err, microapp.ErrorAccessTokenExpire)
+	}
+}
+
+func TestAuditReleaseRollback(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	if err := Audit(env.MicroApp, AuditRequest{}); err == nil {
+		t.Error("missing hostNames should fail validation")
+	}
+	if err := Audit(env.MicroApp, AuditRequest{HostNames: []string{"douyin"}}); err != nil {
+		t.Fatal(err)
+	}
+	env.Capture.AssertJSONField(t, apiAudit, "hostNames", []interface{}{"douyin"})
+
+	if err := Release(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+	env.Capture.AssertMethod(t, apiRelease, http.MethodPost)
+
+	env.Mux.HandleFunc(apiRollback, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errno":40014,"message":"no previous version"}`))
+	})
+	var apiError *APIError
+	if err := Rollback(env.MicroApp); !errors.As(err, &apiError) || apiError.Errno != 40014 {
+		t.Errorf("Rollback() err = %v, want *APIError", err)
+	}
+
+	ctx := microapp.New(microapp.Config{AppId: "APPID"})
+	if err := Release(ctx); err != ErrorNoComponentAppId {
+		t.Errorf("without component_appid: err = %v", err)
+	}
+}
+
+func TestWaitAudit(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	var calls int32
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		status := AuditStatusAuditing
+		if atomic.AddInt32(&calls, 1) >= 3 {
+			status = AuditStatusReject
+		}
+		_, _ = fmt.Fprintf(w, `{"errno":0,"message":"success","data":{"latest":{"version":"1.0.1"},"audit":{"version":"1.0.1","status":%d,"reason":"页面白屏"}}}`, status)
+	})
+
+	audit, err := WaitAudit(context.Background(), env.MicroApp, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audit.Status != AuditStatusReject || audit.Reason != "页面白屏" || audit.Version != "1.0.1" || calls != 3 {
+		t.Errorf("audit = %+v after %d calls", audit, calls)
+	}
+}
+
+func TestWaitAuditCancel(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errno":0,"message":"success","data":{"audit":{"version":"1.0.1","status":0}}}`))
+	})
+
+	c, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	audit, err := WaitAudit(c, env.MicroApp, 10*time.Millisecond)
+	if err != context.DeadlineExceeded || audit.Status != AuditStatusAuditing {
+		t.Errorf("WaitAudit() = %+v, %v, want deadline exceeded", audit, err)
+	}
+}
+
+func TestWaitAuditInFlightCancel(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	var calls int32
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"errno":0,"message":"success","data":{"audit":{"version":"1.0.1","status":0}}}`))
+			return
+		}
+		// 第二次查询 一直不返回，直到 请求 被取消
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	c, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	audit, err := WaitAudit(c, env.MicroApp, 10*time.Millisecond)
+	if err != context.DeadlineExceeded || audit.Version != "1.0.1" {
+		t.Errorf("WaitAudit() = %+v, %v, want last audit and deadline exceeded", audit, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("WaitAudit() returned after %v, want in-flight request canceled", elapsed)
+	}
+}
+
+func TestWaitAuditInterval(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"errno":0,"message":"success","data":{"audit":{"version":"1.0.1","status":%d}}}`, AuditStatusPass)
+	})
+
+	// interval 不大于 0 时 使用 默认间隔，不 panic
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if audit, err := WaitAudit(context.Background(), env.MicroApp, interval); err != nil || audit.Status != AuditStatusPass {
+			t.Errorf("WaitAudit(%v) = %+v, %v", interval, audit, err)
+		}
+	}
+}
+
+func TestWaitAuditNoAudit(t *testing.T) {
+	t.Parallel()
+	env := newEnv(t)
+
+	env.Mux.HandleFunc(apiVersions, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errno":0,"message":"success","data":{"current":{"version":"1.0.0"}}}`))
+	})
+
+	if _, err := WaitAudit(context.Background(), env.MicroApp, time.Millisecond); err != ErrorNoAudit {
+		t.Errorf("err = %v, want ErrorNoAudit", err)
+	}
+	versions, err := Versions(env.MicroApp)
+	if err != nil || versions.Current == nil || versions.Current.Version != "1.0.0" || versions.Latest != nil {
+		t.Errorf("Versions() = %+v, %v", versions, err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package code_management
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fastwego/microapp"
+)
+
+// UploadRequest 上传代码 请求参数
+type UploadRequest struct {
+	TemplateId  int64  `json:"template_id"`  // 代码模板 id
+	UserDesc    string `json:"user_desc"`    // 版本描述
+	UserVersion string `json:"user_version"` // 版本号，如 1.0.0
+	ExtJson     string `json:"ext_json"`     // 第三方自定义配置，JSON 字符串，如 {"extEnable":true,"extAppid":"APPID"}
+}
+
+// Validate 校验必填参数
+func (req UploadRequest) Validate() error {
+	switch {
+	case req.TemplateId <= 0:
+		return errors.New("template_id is required")
+	case req.UserDesc == "":
+		return errors.New("user_desc is required")
+	case req.UserVersion == "":
+		return errors.New("user_version is required")
+	case !json.Valid([]byte(req.ExtJson)):
+		return errors.New("ext_json must be a JSON string")
+	}
+	return nil
+}
+
+/*
+上传代码
+
+为授权小程序 上传 代码模板，生成 测试版本
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/upload-code
+
+POST https://open.microapp.bytedance.com/openapi/v1/microapp/package/upload
+*/
+func Upload(ctx *microapp.MicroApp, req UploadRequest) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	return do(ctx, http.MethodPost, apiUpload, req, nil)
+}
+
+// AuditRequest 提审代码 请求参数
+type AuditRequest struct {
+	HostNames []string `json:"hostNames"` // 审核的宿主 app 名称，如 toutiao douyin
+}
+
+// Validate 校验必填参数
+func (req AuditRequest) Validate() error {
+	if len(req.HostNames) == 0 {
+		return errors.New("hostNames is required")
+	}
+	return nil
+}
+
+/*
+提审代码
+
+将 测试版本 提交审核，审核结果 见 Versions 或 WaitAudit
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/audit-code
+
+POST https://open.microapp.bytedance.com/openapi/v1/microapp/package/audit
+*/
+func Audit(ctx *microapp.MicroApp, req AuditRequest) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	return do(ctx, http.MethodPost, apiAudit, req, nil)
+}
+
+// 审核状态
+const (
+	AuditStatusAuditing = 0 // 审核中
+	AuditStatusPass     = 1 // 通过
+	AuditStatusReject   = 2 // 不通过
+)
+
+// PackageVersion 代码版本
+type PackageVersion struct {
+	Version         string `json:"version"`          // 版本号
+	Summary         string `json:"summary"`          // 版本描述
+	DeveloperAvatar string `json:"developer_avatar"` // 开发者头像
+	DeveloperId     string `json:"developer_id"`     // 开发者 id
+	DeveloperName   string `json:"developer_name"`   // 开发者名称
+	Ctime           int64  `json:"ctime"`            // 创建时间，unix 时间戳
+}
+
+// CurrentVersion 线上版本
+type CurrentVersion struct {
+	PackageVersion
+	HasDown bool `json:"has_down"` // 是否已下架
+}
+
+// AuditVersion 审核版本
+type AuditVersion struct {
+	PackageVersion
+	Status     int    `json:"status"`      // 审核状态 0 审核中 1 通过 2 不通过
+	Reason     string `json:"reason"`      // 审核不通过的原因
+	HasPublish bool   `json:"has_publish"` // 是否已发布
+}
+
+// VersionInfo 版本信息，没有对应版本时 为 nil
+type VersionInfo struct {
+	Current *CurrentVersion `json:"current"` // 线上版本
+	Audit   *AuditVersion   `json:"audit"`   // 审核版本
+	Latest  *PackageVersion `json:"latest"`  // 测试版本
+}
+
+/*
+查询版本
+
+查询 线上版本、审核版本 和 测试版本，审核版本 包含 审核状态
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/get-version-list
+
+GET https://open.microapp.bytedance.com/openapi/v1/microapp/package/versions
+*/
+func Versions(ctx *microapp.MicroApp) (versions VersionInfo, err error) {
+	err = do(ctx, http.MethodGet, apiVersions, nil, &versions)
+	return
+}
+
+/*
+发布代码
+
+将 审核通过的版本 发布上线
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/release-code
+
+POST https://open.microapp.bytedance.com/openapi/v1/microapp/package/release
+*/
+func Release(ctx *microapp.MicroApp) (err error) {
+	return do(ctx, http.MethodPost, apiRelease, nil, nil)
+}
+
+/*
+回退代码版本
+
+将 线上版本 回退到 上一个版本
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/rollback-code
+
+POST https://open.microapp.bytedance.com/openapi/v1/microapp/package/rollback
+*/
+func Rollback(ctx *microapp.MicroApp) (err error) {
+	return do(ctx, http.MethodPost, apiRollback, nil, nil)
+}
@@ -17,7 +17,10 @@ package content_security
 
 import (
 	"bytes"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path"
 
 	"github.com/fastwego/microapp"
 )
@@ -25,6 +28,7 @@ import (
 const (
 	apiTextAntiDirty = "/api/v2/tags/text/antidirt"
 	apiImage         = "/api/v2/tags/image/"
+	apiImageFile     = "/api/v2/tags/image/"
 )
 
 /*
@@ -37,7 +41,7 @@ See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-s
 POST https://developer.toutiao.com/api/v2/tags/text/antidirt
 */
 func TextAntiDirty(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
-	req, err := http.NewRequest(http.MethodPost, microapp.ServerUrl+apiTextAntiDirty, bytes.NewReader(payload))
+	req, err := ctx.Client.NewRequest(http.MethodPost, apiTextAntiDirty, bytes.NewReader(payload))
 	if err != nil {
 		return
 	}
@@ -63,8 +67,7 @@ See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-s
 POST https://developer.toutiao.com/api/v2/tags/image/
 */
 func Image(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
-
-	req, err := http.NewRequest(http.MethodPost, microapp.ServerUrl+apiImage, bytes.NewReader(payload))
+	req, err := ctx.Client.NewRequest(http.MethodPost, apiImage, bytes.NewReader(payload))
 	if err != nil {
 		return
 	}
@@ -79,3 +82,58 @@ func Image(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
 
 	return ctx.Client.HTTPDo(req)
 }
+
+/*
+图片检测（文件上传）
+
+以 multipart/form-data 上传图片文件，检测图片是否包含违法违规内容。targets 为逗号分隔的图片检测服务类型
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/picture-detect
+
+POST(@image|field=targets) https://developer.toutiao.com/api/v2/tags/image/
+*/
+func ImageFile(ctx *microapp.MicroApp, filename string, image io.Reader, fields map[string]string) (resp []byte, err error) {
+	// 表单 边读边写，不在内存中 缓存 整个文件
+	r, w := io.Pipe()
+	m := multipart.NewWriter(w)
+
+	written := make(chan error, 1)
+	go func() {
+		part, err := m.CreateFormFile("image", path.Base(filename))
+		if err == nil {
+			_, err = io.Copy(part, image)
+		}
+		for _, name := range []string{"targets"} {
+			if value, ok := fields[name]; ok && err == nil {
+				err = m.WriteField(name, value)
+			}
+		}
+		if err == nil {
+			err = m.Close()
+		}
+		_ = w.CloseWithError(err)
+		written <- err
+	}()
+	defer func() {
+		// 请求 提前结束 时 关闭 r 使 写入 退出；写入 失败 时 返回 写入的错误
+		_ = r.Close()
+		if werr := <-written; werr != nil && werr != io.ErrClosedPipe {
+			resp, err = nil, werr
+		}
+	}()
+
+	req, err := ctx.Client.NewRequest(http.MethodPost, apiImageFile, r)
+	if err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+	req.Header.Add("X-Token", accessToken)
+	req.Header.Add("Content-Type", m.FormDataContentType())
+
+	return ctx.Client.HTTPDo(req)
+}
@@ -15,9 +15,10 @@
 package content_security
 
 import (
-	"net/http"
+	"io"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fastwego/microapp"
@@ -30,13 +31,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestTextAntiDirty(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiTextAntiDirty, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiTextAntiDirty)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -48,31 +43,24 @@ func TestTextAntiDirty(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: []byte(mock.Response), wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := TextAntiDirty(tt.args.ctx, tt.args.payload)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TextAntiDirty() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("TextAntiDirty() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
 }
 func TestImage(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiImage, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiImage)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -84,20 +72,50 @@ func TestImage(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: []byte(mock.Response), wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := Image(tt.args.ctx, tt.args.payload)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Image() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("Image() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
 }
+func TestImageFile(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiImageFile)
+
+	type args struct {
+		ctx      *microapp.MicroApp
+		filename string
+		image    io.Reader
+		fields   map[string]string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, filename: "image.png", image: strings.NewReader("IMAGE"), fields: map[string]string{}}, wantResp: []byte(mock.Response), wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := ImageFile(tt.args.ctx, tt.args.filename, tt.args.image, tt.args.fields)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImageFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("ImageFile() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/microapp"
+)
+
+// TextTask 检测任务列表
+type TextTask struct {
+	Content string `json:"content"` // 检测的文本内容
+}
+
+// Validate 校验必填参数
+func (req TextTask) Validate() error {
+	if req.Content == "" {
+		return errors.New("content is required")
+	}
+
+	return nil
+}
+
+// TextAntiDirtyRequest 内容安全检测 请求参数
+type TextAntiDirtyRequest struct {
+	Tasks []TextTask `json:"tasks"` // 检测任务列表
+}
+
+// Validate 校验必填参数
+func (req TextAntiDirtyRequest) Validate() error {
+	if len(req.Tasks) == 0 {
+		return errors.New("tasks is required")
+	}
+
+	for i, item := range req.Tasks {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("tasks[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// TextPredict 判定结果
+type TextPredict struct {
+	Prob      float64 `json:"prob"`       // 检测结果置信度，1 为违规
+	ModelName string  `json:"model_name"` // 检测结果模型
+	Target    string  `json:"target"`
+}
+
+// TextResult 检测结果列表
+type TextResult struct {
+	Msg      string        `json:"msg"`
+	Code     int           `json:"code"`     // 检测结果状态码
+	TaskId   string        `json:"task_id"`  // 检测任务 id
+	Predicts []TextPredict `json:"predicts"` // 判定结果
+	DataId   string        `json:"data_id"`
+}
+
+// TextAntiDirtyResponse 内容安全检测 响应
+type TextAntiDirtyResponse struct {
+	LogId string       `json:"log_id"` // 请求 id
+	Data  []TextResult `json:"data"`   // 检测结果列表
+}
+
+// ImageTask 检测任务列表，image 和 image_data 二选一
+type ImageTask struct {
+	Image     string `json:"image,omitempty"`      // 检测的图片链接
+	ImageData string `json:"image_data,omitempty"` // 图片数据的 base64 格式
+}
+
+// ImageRequest 图片检测 请求参数
+type ImageRequest struct {
+	Targets []string    `json:"targets,omitempty"` // 图片检测服务类型 ad porn politics disgusting
+	Tasks   []ImageTask `json:"tasks"`             // 检测任务列表，image 和 image_data 二选一
+}
+
+// Validate 校验必填参数
+func (req ImageRequest) Validate() error {
+	if len(req.Tasks) == 0 {
+		return errors.New("tasks is required")
+	}
+
+	return nil
+}
+
+// ImagePredict 判定结果
+type ImagePredict struct {
+	ModelName string `json:"model_name"` // 检测结果模型
+	Hit       bool   `json:"hit"`        // 是否命中
+}
+
+// ImageResult 检测结果列表
+type ImageResult struct {
+	Msg      string         `json:"msg"`
+	Code     int            `json:"code"`     // 检测结果状态码
+	TaskId   string         `json:"task_id"`  // 检测任务 id
+	Predicts []ImagePredict `json:"predicts"` // 判定结果
+	DataId   string         `json:"data_id"`
+	Cached   bool           `json:"cached"`
+}
+
+// ImageResponse 图片检测 响应
+type ImageResponse struct {
+	LogId string        `json:"log_id"` // 请求 id
+	Data  []ImageResult `json:"data"`   // 检测结果列表
+}
+
+/*
+内容安全检测
+
+使用结构体 作为请求参数和响应 的 TextAntiDirty，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/content-security-detect
+
+POST https://developer.toutiao.com/api/v2/tags/text/antidirt
+*/
+func TextAntiDirtyTyped(ctx *microapp.MicroApp, req TextAntiDirtyRequest) (resp TextAntiDirtyResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	raw, err := TextAntiDirty(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+图片检测
+
+使用结构体 作为请求参数和响应 的 Image，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/picture-detect
+
+POST https://developer.toutiao.com/api/v2/tags/image/
+*/
+func ImageTyped(ctx *microapp.MicroApp, req ImageRequest) (resp ImageResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	raw, err := Image(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
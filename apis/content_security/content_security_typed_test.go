@@ -0,0 +1,76 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_security
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestTextAntiDirtyTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     TextAntiDirtyRequest
+		wantErr bool
+	}{
+		{name: "case1", req: TextAntiDirtyRequest{Tasks: []TextTask{{Content: "test"}}}, wantErr: false},
+		{name: "required", req: TextAntiDirtyRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := TextAntiDirtyTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TextAntiDirtyTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.LogId != "LOG_ID" {
+				t.Errorf("resp.LogId = %v, want %v", resp.LogId, "LOG_ID")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiTextAntiDirty, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiTextAntiDirty, "tasks.0.content", "test")
+}
+
+func TestImageTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     ImageRequest
+		wantErr bool
+	}{
+		{name: "case1", req: ImageRequest{Tasks: []ImageTask{{}}, Targets: []string{"test"}}, wantErr: false},
+		{name: "required", req: ImageRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ImageTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImageTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.LogId != "LOG_ID" {
+				t.Errorf("resp.LogId = %v, want %v", resp.LogId, "LOG_ID")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiImage, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiImage, "targets.0", "test")
+}
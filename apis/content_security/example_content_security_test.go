@@ -16,6 +16,7 @@ package content_security_test
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fastwego/microapp"
 	"github.com/fastwego/microapp/apis/content_security"
@@ -38,3 +39,14 @@ func ExampleImage() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleImageFile() {
+	var ctx *microapp.MicroApp
+
+	filename := ""
+	image, _ := os.Open(filename)
+	fields := map[string]string{}
+	resp, err := content_security.ImageFile(ctx, filename, image, fields)
+
+	fmt.Println(resp, err)
+}
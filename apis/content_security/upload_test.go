@@ -0,0 +1,133 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_security
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestImageFileMultipart(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	var gotImage, gotTargets, gotToken string
+	env.Mux.HandleFunc(apiImageFile, func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("X-Token")
+		gotTargets = r.FormValue("targets")
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		gotImage = header.Filename + ":" + string(content)
+		_, _ = w.Write([]byte(`{"log_id":"LOG_ID","data":[]}`))
+	})
+
+	_, err := ImageFile(env.MicroApp, "/tmp/a.png", strings.NewReader("PNG"), map[string]string{"targets": "porn,ad", "ignored": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotImage != "a.png:PNG" || gotTargets != "porn,ad" || gotToken != "ACCESS_TOKEN" {
+		t.Errorf("image = %s, targets = %s, X-Token = %s", gotImage, gotTargets, gotToken)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestImageFileReaderError(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	// 读取失败 时 返回 读取的错误，而不是 发送请求的错误
+	_, err := ImageFile(env.MicroApp, "a.png", failingReader{}, nil)
+	if err == nil || err.Error() != "read failed" {
+		t.Errorf("ImageFile() error = %v, want reader error", err)
+	}
+}
+
+func TestImageFileStream(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+	if _, err := env.MicroApp.GetAccessTokenHandler(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不经过 Capture（会读取 完整请求体），服务端 收到请求头 时 文件 还没有读完
+	started := make(chan struct{})
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		_, _ = w.Write([]byte(`{"log_id":"` + string(content) + `","data":[]}`))
+	}))
+	defer svr.Close()
+	env.MicroApp.Client.ServerUrl = svr.URL
+
+	image, writer := io.Pipe()
+	go func() {
+		select {
+		case <-started:
+			_, _ = writer.Write([]byte("PNG"))
+			_ = writer.Close()
+		case <-time.After(5 * time.Second):
+			_ = writer.CloseWithError(errors.New("upload buffered before sending"))
+		}
+	}()
+
+	resp, err := ImageFile(env.MicroApp, "a.png", image, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(resp), `"log_id":"PNG"`) {
+		t.Errorf("resp = %s", resp)
+	}
+}
+
+func TestImageFileAccessTokenExpire(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	var notices int
+	notice := env.MicroApp.NoticeAccessTokenExpireHandler
+	env.MicroApp.NoticeAccessTokenExpireHandler = func(ctx *microapp.MicroApp) error {
+		notices++
+		return notice(ctx)
+	}
+	env.Faults.Inject(apiImageFile, test.Fault{Status: http.StatusUnauthorized})
+
+	// 流式上传 无法重放，通知过期 但 不重试
+	_, err := ImageFile(env.MicroApp, "a.png", strings.NewReader("PNG"), nil)
+	if err != microapp.ErrorAccessTokenExpire {
+		t.Errorf("ImageFile() error = %v, want %v", err, microapp.ErrorAccessTokenExpire)
+	}
+	if calls := env.Faults.Calls(apiImageFile); calls != 1 || notices != 1 {
+		t.Errorf("calls = %d, notices = %d, want notice without retry", calls, notices)
+	}
+}
@@ -15,7 +15,6 @@
 package data_caching
 
 import (
-	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -31,13 +30,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestSetUserStorage(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiSetUserStorage, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiSetUserStorage)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -51,31 +44,25 @@ func TestSetUserStorage(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, params: url.Values{"access_token": {"ACCESS_TOKEN"}, "openid": {"OPENID"}, "signature": {"SIGNATURE"}, "sig_method": {"SIG_METHOD"}}}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp, params: url.Values{"openid": {"OPENID"}, "signature": {"SIGNATURE"}, "sig_method": {"SIG_METHOD"}}}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := SetUserStorage(tt.args.ctx, tt.args.payload, tt.args.params)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SetUserStorage() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("SetUserStorage() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
 }
 func TestRemoveUserStorage(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiRemoveUserStorage, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiRemoveUserStorage)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -89,18 +76,18 @@ func TestRemoveUserStorage(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, params: url.Values{"access_token": {"ACCESS_TOKEN"}, "openid": {"OPENID"}, "signature": {"SIGNATURE"}, "sig_method": {"SIG_METHOD"}}}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp, params: url.Values{"openid": {"OPENID"}, "signature": {"SIGNATURE"}, "sig_method": {"SIG_METHOD"}}}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := RemoveUserStorage(tt.args.ctx, tt.args.payload, tt.args.params)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RemoveUserStorage() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("RemoveUserStorage() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
@@ -0,0 +1,191 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_caching
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/fastwego/microapp"
+)
+
+// KvItem 需要存储的 key-value 数据
+type KvItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Validate 校验必填参数
+func (req KvItem) Validate() error {
+	if req.Key == "" {
+		return errors.New("key is required")
+	}
+
+	return nil
+}
+
+// SetUserStorageRequest setUserStorage 请求参数
+type SetUserStorageRequest struct {
+	Openid string   `json:"-"`       // 登录用户唯一标识
+	KvList []KvItem `json:"kv_list"` // 需要存储的 key-value 数据
+}
+
+// Validate 校验必填参数
+func (req SetUserStorageRequest) Validate() error {
+	if req.Openid == "" {
+		return errors.New("openid is required")
+	}
+
+	if len(req.KvList) == 0 {
+		return errors.New("kv_list is required")
+	}
+
+	for i, item := range req.KvList {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("kv_list[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Params query 参数
+func (req SetUserStorageRequest) Params() url.Values {
+	params := url.Values{}
+	params.Add("openid", req.Openid)
+	return params
+}
+
+// SetUserStorageResponse setUserStorage 响应
+type SetUserStorageResponse struct {
+	Errcode int64  `json:"errcode"`
+	Errmsg  string `json:"errmsg"`
+}
+
+// RemoveUserStorageRequest removeUserStorage 请求参数
+type RemoveUserStorageRequest struct {
+	Openid string   `json:"-"`   // 登录用户唯一标识
+	Key    []string `json:"key"` // 要删除的用户数据的 key 列表
+}
+
+// Validate 校验必填参数
+func (req RemoveUserStorageRequest) Validate() error {
+	if req.Openid == "" {
+		return errors.New("openid is required")
+	}
+
+	if len(req.Key) == 0 {
+		return errors.New("key is required")
+	}
+
+	return nil
+}
+
+// Params query 参数
+func (req RemoveUserStorageRequest) Params() url.Values {
+	params := url.Values{}
+	params.Add("openid", req.Openid)
+	return params
+}
+
+// RemoveUserStorageResponse removeUserStorage 响应
+type RemoveUserStorageResponse struct {
+	Errcode int64  `json:"errcode"`
+	Errmsg  string `json:"errmsg"`
+}
+
+/*
+setUserStorage
+
+使用结构体 作为请求参数和响应 的 SetUserStorage，调用前校验必填参数，自动填充 access_token，使用 sessionKey 计算 用户登录态签名
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/data-caching/set-user-storage
+
+POST https://developer.toutiao.com/api/apps/set_user_storage
+*/
+func SetUserStorageTyped(ctx *microapp.MicroApp, sessionKey string, req SetUserStorageRequest) (resp SetUserStorageResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	params := req.Params()
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	params.Set("access_token", accessToken)
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	params.Set("signature", microapp.SessionSignature(sessionKey, payload))
+	params.Set("sig_method", "hmac_sha256")
+
+	raw, err := SetUserStorage(ctx, payload, params)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+removeUserStorage
+
+使用结构体 作为请求参数和响应 的 RemoveUserStorage，调用前校验必填参数，自动填充 access_token，使用 sessionKey 计算 用户登录态签名
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/data-caching/remove-user-storage
+
+POST https://developer.toutiao.com/api/apps/remove_user_storage
+*/
+func RemoveUserStorageTyped(ctx *microapp.MicroApp, sessionKey string, req RemoveUserStorageRequest) (resp RemoveUserStorageResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	params := req.Params()
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	params.Set("access_token", accessToken)
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	params.Set("signature", microapp.SessionSignature(sessionKey, payload))
+	params.Set("sig_method", "hmac_sha256")
+
+	raw, err := RemoveUserStorage(ctx, payload, params)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_caching
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestSetUserStorageTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     SetUserStorageRequest
+		wantErr bool
+	}{
+		{name: "case1", req: SetUserStorageRequest{Openid: "test", KvList: []KvItem{{Key: "test", Value: "test"}}}, wantErr: false},
+		{name: "required", req: SetUserStorageRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := SetUserStorageTyped(env.MicroApp, "SESSION_KEY", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetUserStorageTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.Errmsg != "ok" {
+				t.Errorf("resp.Errmsg = %v, want %v", resp.Errmsg, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiSetUserStorage, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiSetUserStorage, "kv_list.0.key", "test")
+}
+
+func TestRemoveUserStorageTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     RemoveUserStorageRequest
+		wantErr bool
+	}{
+		{name: "case1", req: RemoveUserStorageRequest{Openid: "test", Key: []string{"test"}}, wantErr: false},
+		{name: "required", req: RemoveUserStorageRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := RemoveUserStorageTyped(env.MicroApp, "SESSION_KEY", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RemoveUserStorageTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.Errmsg != "ok" {
+				t.Errorf("resp.Errmsg = %v, want %v", resp.Errmsg, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiRemoveUserStorage, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiRemoveUserStorage, "key.0", "test")
+}
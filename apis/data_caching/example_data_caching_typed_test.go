@@ -0,0 +1,40 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_caching_test
+
+import (
+	"fmt"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/data_caching"
+)
+
+func ExampleSetUserStorageTyped() {
+	var ctx *microapp.MicroApp
+
+	req := data_caching.SetUserStorageRequest{}
+	resp, err := data_caching.SetUserStorageTyped(ctx, "SESSION_KEY", req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleRemoveUserStorageTyped() {
+	var ctx *microapp.MicroApp
+
+	req := data_caching.RemoveUserStorageRequest{}
+	resp, err := data_caching.RemoveUserStorageTyped(ctx, "SESSION_KEY", req)
+
+	fmt.Println(resp, err)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_caching
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/auth"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestUserStorageSignature(t *testing.T) {
+	t.Parallel()
+	env := test.NewPlatformEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	session, err := auth.Code2SessionTyped(env.MicroApp, auth.Code2SessionRequest{Code: "CODE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionKey := session.SessionKey
+
+	// 空值 合法
+	resp, err := SetUserStorageTyped(env.MicroApp, sessionKey, SetUserStorageRequest{Openid: session.Openid, KvList: []KvItem{{Key: "a", Value: "1"}, {Key: "b", Value: ""}}})
+	if err != nil || resp.Errcode != 0 {
+		t.Fatalf("SetUserStorageTyped() = %+v, %v", resp, err)
+	}
+	req := env.Capture.MustLastRequest(t, apiSetUserStorage)
+	if req.Query.Get("signature") != microapp.SessionSignature(sessionKey, req.Body) || req.Query.Get("sig_method") != "hmac_sha256" {
+		t.Errorf("query = %v, want signature over body %s", req.Query, req.Body)
+	}
+
+	if _, err = RemoveUserStorageTyped(env.MicroApp, sessionKey, RemoveUserStorageRequest{Openid: session.Openid, Key: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if storage := env.Platform.Storage(session.Openid); len(storage) != 1 || storage["b"] != "" {
+		t.Errorf("Storage() = %v", storage)
+	}
+
+	if _, err = SetUserStorageTyped(env.MicroApp, "WRONG_KEY", SetUserStorageRequest{Openid: session.Openid, KvList: []KvItem{{Key: "c", Value: "3"}}}); err == nil {
+		t.Error("wrong session_key should be rejected")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package ecpay 担保支付
+
+接口不使用 access_token，请求参数 使用 开发者后台配置的 SALT 签名（见 Sign），SALT 通过 microapp.Config.PaymentSalt 配置；
+app_id 和 sign 自动填充
+
+接口返回 err_no 不为 0 时 返回 *APIError
+
+支付、退款、结算 的结果 通过回调通知，见 NewNotifyHandler
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/server-doc
+*/
+package ecpay
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fastwego/microapp"
+)
+
+const (
+	apiCreateOrder  = "/api/apps/ecpay/v1/create_order"
+	apiQueryOrder   = "/api/apps/ecpay/v1/query_order"
+	apiCreateRefund = "/api/apps/ecpay/v1/create_refund"
+	apiQueryRefund  = "/api/apps/ecpay/v1/query_refund"
+	apiSettle       = "/api/apps/ecpay/v1/settle"
+	apiQuerySettle  = "/api/apps/ecpay/v1/query_settle"
+)
+
+// 错误码 err_no，其他错误码 见 err_tips
+const (
+	ErrNoSuccess = 0
+	ErrNoParam   = 1000 // 参数错误，如 签名错误、订单号重复、金额不合法
+	ErrNoSystem  = 2000 // 系统错误，可稍后重试
+)
+
+// 订单、退款、结算 状态
+const (
+	StatusProcessing = "PROCESSING" // 处理中
+	StatusSuccess    = "SUCCESS"    // 成功
+	StatusFail       = "FAIL"       // 失败
+	StatusTimeout    = "TIMEOUT"    // 超时未支付，仅订单
+)
+
+var (
+	ErrorNoPaymentSalt  = errors.New("payment salt not configured")
+	ErrorNoPaymentToken = errors.New("payment token not configured")
+)
+
+/*
+APIError 担保支付 接口错误，err_no 不为 0
+*/
+type APIError struct {
+	ErrNo   int64  `json:"err_no"`
+	ErrTips string `json:"err_tips"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ecpay err_no %d: %s", e.ErrNo, e.ErrTips)
+}
+
+// Temporary 系统错误，可稍后重试
+func (e *APIError) Temporary() bool {
+	return e.ErrNo == ErrNoSystem
+}
+
+// Result 响应中的 错误码 和 错误信息，嵌入在 各接口的响应中
+type Result struct {
+	ErrNo   int64  `json:"err_no"`
+	ErrTips string `json:"err_tips"`
+}
+
+func (result Result) result() Result {
+	return result
+}
+
+// 不参与签名的参数
+var unsignedParams = map[string]bool{
+	"app_id":              true,
+	"thirdparty_id":       true,
+	"sign":                true,
+	"other_settle_params": true,
+}
+
+/*
+Sign 计算 请求签名
+
+ 1. 除 app_id thirdparty_id sign other_settle_params 外，取 全部非空参数的值（对象 和 数组 使用 JSON 字符串）
+ 2. 加入 SALT，按字典序排序，使用 & 拼接
+ 3. 计算 MD5，输出 小写十六进制
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/server-doc
+*/
+func Sign(salt string, params map[string]interface{}) string {
+	values := []string{salt}
+	for name, param := range params {
+		if unsignedParams[name] {
+			continue
+		}
+
+		value := signValue(param)
+		if value == "" || value == "null" {
+			continue
+		}
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	sum := md5.Sum([]byte(strings.Join(values, "&")))
+	return hex.EncodeToString(sum[:])
+}
+
+func signValue(param interface{}) string {
+	switch value := param.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case json.Number:
+		return value.String()
+	case fmt.Stringer:
+		return strings.TrimSpace(value.String())
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	return strings.TrimSpace(fmt.Sprint(param))
+}
+
+/*
+post 填充 app_id 和 sign 后 发送请求，解析响应到 resp
+
+resp 为 嵌入 Result 的响应结构 的指针，err_no 不为 0 时 返回 *APIError
+*/
+func post(ctx *microapp.MicroApp, uri string, req interface{}, resp interface{ result() Result }) (err error) {
+	if ctx.Config.PaymentSalt == "" {
+		return ErrorNoPaymentSalt
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+	params := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&params); err != nil {
+		return
+	}
+	params["app_id"] = ctx.Config.AppId
+	params["sign"] = Sign(ctx.Config.PaymentSalt, params)
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+
+	raw, err := ctx.Client.HTTPPost(uri, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(raw, resp); err != nil {
+		return
+	}
+	if result := resp.result(); result.ErrNo != ErrNoSuccess {
+		return &APIError{ErrNo: result.ErrNo, ErrTips: result.ErrTips}
+	}
+	return
+}
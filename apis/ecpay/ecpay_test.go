@@ -0,0 +1,169 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+var testConfig = microapp.Config{AppId: "APPID", AppSecret: "SECRET", PaymentSalt: "SALT"}
+
+func TestSign(t *testing.T) {
+	params := map[string]interface{}{
+		"app_id":        "APPID",
+		"thirdparty_id": "THIRDPARTY_ID",
+		"out_order_no":  "ORDER_1",
+		"total_amount":  1,
+		"subject":       "S",
+		"body":          " B ",
+		"valid_time":    300,
+		"cp_extra":      "",
+		"notify_url":    nil,
+	}
+
+	// md5("1&300&B&ORDER_1&S&SALT")
+	if got, want := Sign("SALT", params), "a852d8055d1b3fc559c5e7d73b4d1c97"; got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+
+	params["sign"] = "IGNORED"
+	if got := Sign("SALT", params); got != "a852d8055d1b3fc559c5e7d73b4d1c97" {
+		t.Errorf("sign should not be signed, got %s", got)
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, testConfig)
+
+	req := CreateOrderRequest{OutOrderNo: "ORDER_1", TotalAmount: 1, Subject: "S", Body: "B", ValidTime: 300}
+	resp, err := CreateOrder(env.MicroApp, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.OrderId != "ORDER_ID" || resp.Data.OrderToken != "ORDER_TOKEN" {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	env.Capture.AssertJSONField(t, apiCreateOrder, "app_id", "APPID")
+	env.Capture.AssertJSONField(t, apiCreateOrder, "total_amount", float64(1))
+	env.Capture.AssertJSONField(t, apiCreateOrder, "sign", "a852d8055d1b3fc559c5e7d73b4d1c97")
+}
+
+func TestQueries(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, testConfig)
+
+	tests := []struct {
+		name string
+		path string
+		call func() (interface{}, error)
+		want func(resp interface{}) bool
+	}{
+		{
+			name: "QueryOrder",
+			path: apiQueryOrder,
+			call: func() (interface{}, error) { return QueryOrder(env.MicroApp, QueryOrderRequest{OutOrderNo: "ORDER_1"}) },
+			want: func(resp interface{}) bool {
+				return resp.(QueryOrderResponse).PaymentInfo.OrderStatus == "ORDER_STATUS"
+			},
+		},
+		{
+			name: "CreateRefund",
+			path: apiCreateRefund,
+			call: func() (interface{}, error) {
+				return CreateRefund(env.MicroApp, CreateRefundRequest{OutOrderNo: "ORDER_1", OutRefundNo: "REFUND_1", Reason: "R", RefundAmount: 1})
+			},
+			want: func(resp interface{}) bool { return resp.(CreateRefundResponse).RefundNo == "REFUND_NO" },
+		},
+		{
+			name: "QueryRefund",
+			path: apiQueryRefund,
+			call: func() (interface{}, error) {
+				return QueryRefund(env.MicroApp, QueryRefundRequest{OutRefundNo: "REFUND_1"})
+			},
+			want: func(resp interface{}) bool { return resp.(QueryRefundResponse).RefundInfo.RefundNo == "REFUND_NO" },
+		},
+		{
+			name: "Settle",
+			path: apiSettle,
+			call: func() (interface{}, error) {
+				return Settle(env.MicroApp, SettleRequest{OutOrderNo: "ORDER_1", OutSettleNo: "SETTLE_1", SettleDesc: "D"})
+			},
+			want: func(resp interface{}) bool { return resp.(SettleResponse).SettleNo == "SETTLE_NO" },
+		},
+		{
+			name: "QuerySettle",
+			path: apiQuerySettle,
+			call: func() (interface{}, error) {
+				return QuerySettle(env.MicroApp, QuerySettleRequest{OutSettleNo: "SETTLE_1"})
+			},
+			want: func(resp interface{}) bool { return resp.(QuerySettleResponse).SettleInfo.SettleNo == "SETTLE_NO" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.want(resp) {
+				t.Errorf("resp = %+v", resp)
+			}
+
+			req := env.Capture.MustLastRequest(t, tt.path)
+			if sign, _ := req.JSONField("sign"); sign == "" || sign == nil {
+				t.Error("request is not signed")
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, testConfig)
+	env.Mux.HandleFunc(apiQueryOrder, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"err_no":2000,"err_tips":"系统错误"}`))
+	})
+
+	_, err := QueryOrder(env.MicroApp, QueryOrderRequest{OutOrderNo: "ORDER_1"})
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.ErrNo != ErrNoSystem || !apiError.Temporary() {
+		t.Errorf("err = %v, want temporary *APIError", err)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	if _, err := QueryOrder(env.MicroApp, QueryOrderRequest{OutOrderNo: "ORDER_1"}); err != ErrorNoPaymentSalt {
+		t.Errorf("err = %v, want %v", err, ErrorNoPaymentSalt)
+	}
+
+	env.MicroApp.Config.PaymentSalt = "SALT"
+	if _, err := CreateOrder(env.MicroApp, CreateOrderRequest{OutOrderNo: "ORDER_1", TotalAmount: 1, Subject: "S", Body: "B", ValidTime: 60}); err == nil {
+		t.Error("valid_time 60 should be rejected")
+	}
+	if _, ok := env.Capture.LastRequest(apiCreateOrder); ok {
+		t.Error("invalid request should not be sent")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/ecpay"
+)
+
+func ExampleCreateOrder() {
+	ctx := microapp.New(microapp.Config{AppId: "APPID", AppSecret: "SECRET", PaymentSalt: "SALT"})
+
+	resp, err := ecpay.CreateOrder(ctx, ecpay.CreateOrderRequest{
+		OutOrderNo:  "ORDER_1",
+		TotalAmount: 100,
+		Subject:     "商品",
+		Body:        "商品详情",
+		ValidTime:   900,
+	})
+
+	var apiError *ecpay.APIError
+	if errors.As(err, &apiError) {
+		fmt.Println(apiError.ErrNo, apiError.ErrTips)
+	}
+	fmt.Println(resp.Data.OrderId, resp.Data.OrderToken)
+}
+
+func ExampleSign() {
+	sign := ecpay.Sign("SALT", map[string]interface{}{
+		"out_order_no": "ORDER_1",
+		"total_amount": 1,
+		"subject":      "S",
+		"body":         "B",
+		"valid_time":   300,
+	})
+
+	fmt.Println(sign)
+	// Output: a852d8055d1b3fc559c5e7d73b4d1c97
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/server"
+)
+
+// 回调类型
+const (
+	NotifyTypePayment = "payment" // 支付结果
+	NotifyTypeRefund  = "refund"  // 退款结果
+	NotifyTypeSettle  = "settle"  // 结算结果
+)
+
+/*
+NewNotifyHandler 创建 支付、退款、结算 回调的处理器
+
+使用 microapp.Config.PaymentToken 校验 msg_signature，msg 解析为 *PaymentEvent *RefundEvent *SettleEvent 后 调用 callback；
+callback 返回 nil 时 应答 {"err_no":0,"err_tips":"success"}，否则 err_no 不为 0，平台 会重新推送；
+签名错误时 同样应答 err_no 不为 0 的 Ack。未配置 PaymentToken 时 任何人都能伪造签名，返回 ErrorNoPaymentToken
+
+	handler, err := ecpay.NewNotifyHandler(ctx, func(event server.Event) error {
+		switch e := event.(type) {
+		case *ecpay.PaymentEvent:
+			...
+		}
+		return nil
+	})
+	if err != nil {
+		...
+	}
+	http.Handle("/ecpay/notify", handler)
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/server-doc
+*/
+func NewNotifyHandler(ctx *microapp.MicroApp, callback server.HandlerFunc) (srv *server.Server, err error) {
+	if ctx.Config.PaymentToken == "" {
+		return nil, ErrorNoPaymentToken
+	}
+
+	srv = server.New(ctx.Config.PaymentToken)
+	srv.Logger = ctx.Logger
+
+	srv.RegisterEvent(NotifyTypePayment, func() server.Event { return &PaymentEvent{} })
+	srv.RegisterEvent(NotifyTypeRefund, func() server.Event { return &RefundEvent{} })
+	srv.RegisterEvent(NotifyTypeSettle, func() server.Event { return &SettleEvent{} })
+
+	srv.Handle(NotifyTypePayment, callback)
+	srv.Handle(NotifyTypeRefund, callback)
+	srv.Handle(NotifyTypeSettle, callback)
+	return
+}
+
+/*
+PaymentEvent 支付结果回调
+*/
+type PaymentEvent struct {
+	server.Message `json:"-"`
+
+	AppId            string `json:"appid"`
+	CpOrderno        string `json:"cp_orderno"`         // 开发者侧的订单号
+	CpExtra          string `json:"cp_extra"`           // 预下单时 开发者传入的自定义字段
+	Way              string `json:"way"`                // 支付渠道 1 微信 2 支付宝 10 抖音支付
+	ChannelNo        string `json:"channel_no"`         // 支付渠道侧的单号
+	PaymentOrderNo   string `json:"payment_order_no"`   // 支付渠道侧的商户单号
+	TotalAmount      int64  `json:"total_amount"`       // 支付金额，单位为 分
+	Status           string `json:"status"`             // 固定 SUCCESS
+	ItemId           string `json:"item_id"`            // 视频 id
+	SellerUid        string `json:"seller_uid"`         // 卖家商户号
+	PaidAt           int64  `json:"paid_at"`            // 支付时间，unix 时间戳
+	OrderId          string `json:"order_id"`           // 抖音侧的订单号
+	Extra            string `json:"extra"`              // 其他信息
+	ChannelGatewayNo string `json:"channel_gateway_no"` // 支付渠道侧的网关单号
+}
+
+/*
+RefundEvent 退款结果回调
+*/
+type RefundEvent struct {
+	server.Message `json:"-"`
+
+	AppId         string `json:"appid"`
+	CpRefundno    string `json:"cp_refundno"`    // 开发者侧的退款单号
+	CpExtra       string `json:"cp_extra"`       // 退款时 开发者传入的自定义字段
+	Status        string `json:"status"`         // 退款状态 PROCESSING SUCCESS FAIL
+	RefundAmount  int64  `json:"refund_amount"`  // 退款金额，单位为 分
+	IsAllSettled  bool   `json:"is_all_settled"` // 是否已分账完成
+	RefundedAt    int64  `json:"refunded_at"`    // 退款时间，unix 时间戳
+	StatusMessage string `json:"message"`        // 退款失败原因
+	OrderId       string `json:"order_id"`       // 抖音侧的订单号
+	RefundNo      string `json:"refund_no"`      // 抖音侧的退款单号
+}
+
+/*
+SettleEvent 结算结果回调
+*/
+type SettleEvent struct {
+	server.Message `json:"-"`
+
+	AppId         string `json:"appid"`
+	CpSettleNo    string `json:"cp_settle_no"`  // 开发者侧的结算单号
+	CpExtra       string `json:"cp_extra"`      // 结算时 开发者传入的自定义字段
+	Status        string `json:"status"`        // 结算状态 PROCESSING SUCCESS FAIL
+	Rake          int64  `json:"rake"`          // 手续费，单位为 分
+	Commission    int64  `json:"commission"`    // 佣金，单位为 分
+	SettleDetail  string `json:"settle_detail"` // 分账详情
+	SettledAt     int64  `json:"settled_at"`    // 结算时间，unix 时间戳
+	StatusMessage string `json:"message"`       // 结算失败原因
+	OrderId       string `json:"order_id"`      // 抖音侧的订单号
+	SettleAmount  int64  `json:"settle_amount"` // 结算金额，单位为 分
+	SettleNo      string `json:"settle_no"`     // 抖音侧的结算单号
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/server"
+)
+
+// notify 向 handler 发送 使用 token 签名的回调
+func notify(handler http.Handler, token string, msgType string, msg string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{
+		"timestamp":     "1602507471",
+		"nonce":         "797",
+		"msg":           msg,
+		"type":          msgType,
+		"msg_signature": server.Signature(token, "1602507471", "797", msg),
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ecpay/notify", strings.NewReader(string(body))))
+	return w
+}
+
+func TestNotifyHandler(t *testing.T) {
+	ctx := microapp.New(microapp.Config{AppId: "APPID", PaymentToken: "TOKEN"})
+	ctx.Logger = nil
+
+	var events []server.Event
+	handler, err := NewNotifyHandler(ctx, func(event server.Event) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const success = `{"err_no":0,"err_tips":"success"}` + "\n"
+	tests := []struct {
+		msgType string
+		msg     string
+		check   func(event server.Event) bool
+	}{
+		{
+			msgType: NotifyTypePayment,
+			msg:     `{"appid":"APPID","cp_orderno":"ORDER_1","way":"10","total_amount":100,"status":"SUCCESS","paid_at":1602507471,"order_id":"N1"}`,
+			check: func(event server.Event) bool {
+				e, ok := event.(*PaymentEvent)
+				return ok && e.CpOrderno == "ORDER_1" && e.TotalAmount == 100 && e.Way == "10" && e.Envelope().Type == NotifyTypePayment
+			},
+		},
+		{
+			msgType: NotifyTypeRefund,
+			msg:     `{"appid":"APPID","cp_refundno":"REFUND_1","status":"FAIL","refund_amount":50,"message":"余额不足"}`,
+			check: func(event server.Event) bool {
+				e, ok := event.(*RefundEvent)
+				return ok && e.CpRefundno == "REFUND_1" && e.Status == StatusFail && e.StatusMessage == "余额不足"
+			},
+		},
+		{
+			msgType: NotifyTypeSettle,
+			msg:     `{"appid":"APPID","cp_settle_no":"SETTLE_1","status":"SUCCESS","settle_amount":90,"rake":1,"commission":9}`,
+			check: func(event server.Event) bool {
+				e, ok := event.(*SettleEvent)
+				return ok && e.CpSettleNo == "SETTLE_1" && e.SettleAmount == 90 && e.Commission == 9
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msgType, func(t *testing.T) {
+			events = nil
+			w := notify(handler, "TOKEN", tt.msgType, tt.msg)
+			if w.Code != http.StatusOK || w.Body.String() != success {
+				t.Fatalf("ack = %d %s", w.Code, w.Body.String())
+			}
+			if len(events) != 1 || !tt.check(events[0]) {
+				t.Errorf("events = %+v", events)
+			}
+		})
+	}
+}
+
+func TestNotifyHandlerErrors(t *testing.T) {
+	ctx := microapp.New(microapp.Config{AppId: "APPID", PaymentToken: "TOKEN"})
+	ctx.Logger = nil
+
+	called := false
+	handler, err := NewNotifyHandler(ctx, func(event server.Event) error {
+		called = true
+		return errors.New("order not found")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ack server.Ack
+	w := notify(handler, "OTHER_TOKEN", NotifyTypePayment, `{"cp_orderno":"ORDER_1"}`)
+	if err = json.Unmarshal(w.Body.Bytes(), &ack); err != nil {
+		t.Fatalf("wrong token: body %s is not an ack: %v", w.Body.String(), err)
+	}
+	if w.Code != http.StatusForbidden || ack.ErrNo == 0 || ack.ErrTips != server.ErrorSignature.Error() || called {
+		t.Errorf("wrong token: code = %d, ack = %+v, callback called = %v", w.Code, ack, called)
+	}
+
+	w = notify(handler, "TOKEN", NotifyTypePayment, `{"cp_orderno":"ORDER_1"}`)
+	if err = json.Unmarshal(w.Body.Bytes(), &ack); err != nil {
+		t.Fatal(err)
+	}
+	if !called || ack.ErrNo == 0 || ack.ErrTips != "order not found" {
+		t.Errorf("callback error: ack = %+v", ack)
+	}
+}
+
+func TestNotifyHandlerNoToken(t *testing.T) {
+	ctx := microapp.New(microapp.Config{AppId: "APPID", PaymentSalt: "SALT"})
+
+	handler, err := NewNotifyHandler(ctx, func(event server.Event) error { return nil })
+	if err != ErrorNoPaymentToken || handler != nil {
+		t.Errorf("NewNotifyHandler() = %v, %v, want %v", handler, err, ErrorNoPaymentToken)
+	}
+}
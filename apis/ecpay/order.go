@@ -0,0 +1,134 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// CreateOrderRequest 预下单 请求参数
+type CreateOrderRequest struct {
+	OutOrderNo   string `json:"out_order_no"`            // 开发者侧的订单号，同一小程序下不可重复
+	TotalAmount  int64  `json:"total_amount"`            // 支付价格，单位为 分
+	Subject      string `json:"subject"`                 // 商品描述，长度限制 128 字节
+	Body         string `json:"body"`                    // 商品详情
+	ValidTime    int64  `json:"valid_time"`              // 订单过期时间，单位为 秒，最小 300 最大 172800
+	CpExtra      string `json:"cp_extra,omitempty"`      // 开发者自定义字段，回调原样回传
+	NotifyUrl    string `json:"notify_url,omitempty"`    // 支付结果回调地址，为空时 使用后台配置的地址
+	DisableMsg   int64  `json:"disable_msg,omitempty"`   // 是否屏蔽担保支付的推送消息，1 屏蔽 0 不屏蔽
+	MsgPage      string `json:"msg_page,omitempty"`      // 担保支付消息跳转页
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req CreateOrderRequest) Validate() error {
+	switch {
+	case req.OutOrderNo == "":
+		return errors.New("out_order_no is required")
+	case req.TotalAmount <= 0:
+		return errors.New("total_amount must be positive")
+	case req.Subject == "":
+		return errors.New("subject is required")
+	case req.Body == "":
+		return errors.New("body is required")
+	case req.ValidTime < 300 || req.ValidTime > 172800:
+		return errors.New("valid_time must be between 300 and 172800")
+	}
+	return nil
+}
+
+// OrderInfo 拉起支付的参数
+type OrderInfo struct {
+	OrderId    string `json:"order_id"`    // 抖音侧的订单号
+	OrderToken string `json:"order_token"` // 签名后的订单信息
+}
+
+// CreateOrderResponse 预下单 响应
+type CreateOrderResponse struct {
+	Result
+	Data OrderInfo `json:"data"` // 拉起支付的参数
+}
+
+/*
+预下单
+
+发起支付前，在服务端预下单，获得 order_id 和 order_token 供小程序端拉起支付
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/pay-list/pay
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/create_order
+*/
+func CreateOrder(ctx *microapp.MicroApp, req CreateOrderRequest) (resp CreateOrderResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiCreateOrder, req, &resp)
+	return
+}
+
+// QueryOrderRequest 查询订单 请求参数
+type QueryOrderRequest struct {
+	OutOrderNo   string `json:"out_order_no"`            // 开发者侧的订单号
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req QueryOrderRequest) Validate() error {
+	if req.OutOrderNo == "" {
+		return errors.New("out_order_no is required")
+	}
+	return nil
+}
+
+// PaymentInfo 支付信息
+type PaymentInfo struct {
+	TotalFee         int64  `json:"total_fee"`          // 支付金额，单位为 分
+	OrderStatus      string `json:"order_status"`       // 支付状态 PROCESSING SUCCESS FAIL TIMEOUT
+	PayTime          string `json:"pay_time"`           // 支付完成时间
+	Way              int64  `json:"way"`                // 支付渠道 1 微信 2 支付宝 10 抖音支付
+	ChannelNo        string `json:"channel_no"`         // 支付渠道侧的单号
+	ChannelGatewayNo string `json:"channel_gateway_no"` // 支付渠道侧的商户单号
+	SellerUid        string `json:"seller_uid"`         // 卖家商户号
+	ItemId           string `json:"item_id"`            // 视频 id
+}
+
+// QueryOrderResponse 查询订单 响应
+type QueryOrderResponse struct {
+	Result
+	OutOrderNo  string      `json:"out_order_no"` // 开发者侧的订单号
+	OrderId     string      `json:"order_id"`     // 抖音侧的订单号
+	PaymentInfo PaymentInfo `json:"payment_info"` // 支付信息
+}
+
+/*
+查询订单
+
+查询订单的支付状态
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/pay-list/query
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/query_order
+*/
+func QueryOrder(ctx *microapp.MicroApp, req QueryOrderRequest) (resp QueryOrderResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiQueryOrder, req, &resp)
+	return
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// CreateRefundRequest 退款 请求参数
+type CreateRefundRequest struct {
+	OutOrderNo   string `json:"out_order_no"`            // 开发者侧的订单号
+	OutRefundNo  string `json:"out_refund_no"`           // 开发者侧的退款单号
+	Reason       string `json:"reason"`                  // 退款理由
+	RefundAmount int64  `json:"refund_amount"`           // 退款金额，单位为 分
+	CpExtra      string `json:"cp_extra,omitempty"`      // 开发者自定义字段，回调原样回传
+	NotifyUrl    string `json:"notify_url,omitempty"`    // 退款结果回调地址，为空时 使用后台配置的地址
+	DisableMsg   int64  `json:"disable_msg,omitempty"`   // 是否屏蔽担保支付的推送消息，1 屏蔽 0 不屏蔽
+	MsgPage      string `json:"msg_page,omitempty"`      // 担保支付消息跳转页
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req CreateRefundRequest) Validate() error {
+	switch {
+	case req.OutOrderNo == "":
+		return errors.New("out_order_no is required")
+	case req.OutRefundNo == "":
+		return errors.New("out_refund_no is required")
+	case req.Reason == "":
+		return errors.New("reason is required")
+	case req.RefundAmount <= 0:
+		return errors.New("refund_amount must be positive")
+	}
+	return nil
+}
+
+// CreateRefundResponse 退款 响应
+type CreateRefundResponse struct {
+	Result
+	RefundNo string `json:"refund_no"` // 抖音侧的退款单号
+}
+
+/*
+退款
+
+对已支付的订单 发起退款，结果通过 退款回调 通知
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/refund-list/refund
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/create_refund
+*/
+func CreateRefund(ctx *microapp.MicroApp, req CreateRefundRequest) (resp CreateRefundResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiCreateRefund, req, &resp)
+	return
+}
+
+// QueryRefundRequest 查询退款 请求参数
+type QueryRefundRequest struct {
+	OutRefundNo  string `json:"out_refund_no"`           // 开发者侧的退款单号
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req QueryRefundRequest) Validate() error {
+	if req.OutRefundNo == "" {
+		return errors.New("out_refund_no is required")
+	}
+	return nil
+}
+
+// RefundInfo 退款信息
+type RefundInfo struct {
+	RefundNo     string `json:"refund_no"`      // 抖音侧的退款单号
+	RefundAmount int64  `json:"refund_amount"`  // 退款金额，单位为 分
+	RefundStatus string `json:"refund_status"`  // 退款状态 PROCESSING SUCCESS FAIL
+	RefundedAt   int64  `json:"refunded_at"`    // 退款时间，unix 时间戳
+	IsAllSettled bool   `json:"is_all_settled"` // 是否已分账完成
+	CpExtra      string `json:"cp_extra"`       // 开发者自定义字段
+}
+
+// QueryRefundResponse 查询退款 响应
+type QueryRefundResponse struct {
+	Result
+	RefundInfo RefundInfo `json:"refundInfo"` // 退款信息
+}
+
+/*
+查询退款
+
+查询退款的状态
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/refund-list/query
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/query_refund
+*/
+func QueryRefund(ctx *microapp.MicroApp, req QueryRefundRequest) (resp QueryRefundResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiQueryRefund, req, &resp)
+	return
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecpay
+
+import (
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// SettleRequest 结算及分账 请求参数
+type SettleRequest struct {
+	OutOrderNo   string `json:"out_order_no"`            // 开发者侧的订单号
+	OutSettleNo  string `json:"out_settle_no"`           // 开发者侧的结算单号
+	SettleDesc   string `json:"settle_desc"`             // 结算描述
+	SettleParams string `json:"settle_params,omitempty"` // 分账方参数，JSON 字符串
+	CpExtra      string `json:"cp_extra,omitempty"`      // 开发者自定义字段，回调原样回传
+	NotifyUrl    string `json:"notify_url,omitempty"`    // 结算结果回调地址，为空时 使用后台配置的地址
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req SettleRequest) Validate() error {
+	switch {
+	case req.OutOrderNo == "":
+		return errors.New("out_order_no is required")
+	case req.OutSettleNo == "":
+		return errors.New("out_settle_no is required")
+	case req.SettleDesc == "":
+		return errors.New("settle_desc is required")
+	}
+	return nil
+}
+
+// SettleResponse 结算及分账 响应
+type SettleResponse struct {
+	Result
+	SettleNo string `json:"settle_no"` // 抖音侧的结算单号
+}
+
+/*
+结算及分账
+
+订单完成后 发起结算，可同时分账给其他商户
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/settle-list/settle
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/settle
+*/
+func Settle(ctx *microapp.MicroApp, req SettleRequest) (resp SettleResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiSettle, req, &resp)
+	return
+}
+
+// QuerySettleRequest 查询结算 请求参数
+type QuerySettleRequest struct {
+	OutSettleNo  string `json:"out_settle_no"`           // 开发者侧的结算单号
+	ThirdpartyId string `json:"thirdparty_id,omitempty"` // 第三方平台服务商 id，非服务商模式留空
+}
+
+// Validate 校验必填参数
+func (req QuerySettleRequest) Validate() error {
+	if req.OutSettleNo == "" {
+		return errors.New("out_settle_no is required")
+	}
+	return nil
+}
+
+// SettleInfo 结算信息
+type SettleInfo struct {
+	SettleNo     string `json:"settle_no"`     // 抖音侧的结算单号
+	SettleAmount int64  `json:"settle_amount"` // 结算金额，单位为 分
+	SettleStatus string `json:"settle_status"` // 结算状态 PROCESSING SUCCESS FAIL
+	SettledAt    int64  `json:"settled_at"`    // 结算时间，unix 时间戳
+	Rake         int64  `json:"rake"`          // 手续费，单位为 分
+	Commission   int64  `json:"commission"`    // 佣金，单位为 分
+	CpExtra      string `json:"cp_extra"`      // 开发者自定义字段
+}
+
+// QuerySettleResponse 查询结算 响应
+type QuerySettleResponse struct {
+	Result
+	SettleInfo SettleInfo `json:"settle_info"` // 结算信息
+}
+
+/*
+查询结算
+
+查询结算及分账的状态
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/settle-list/query
+
+POST https://developer.toutiao.com/api/apps/ecpay/v1/query_settle
+*/
+func QuerySettle(ctx *microapp.MicroApp, req QuerySettleRequest) (resp QuerySettleResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	err = post(ctx, apiQuerySettle, req, &resp)
+	return
+}
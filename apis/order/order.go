@@ -0,0 +1,235 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package order 订单同步
+package order
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/microapp"
+)
+
+const (
+	apiPush = "/api/apps/order/v2/push"
+)
+
+// OrderStatus 订单状态
+type OrderStatus int64
+
+const (
+	OrderStatusUnpaid       OrderStatus = 0 // 待支付
+	OrderStatusPaid         OrderStatus = 1 // 已支付
+	OrderStatusCanceled     OrderStatus = 2 // 已取消
+	OrderStatusVerified     OrderStatus = 4 // 已核销
+	OrderStatusRefunding    OrderStatus = 5 // 退款中
+	OrderStatusRefunded     OrderStatus = 6 // 已退款
+	OrderStatusRefundFailed OrderStatus = 8 // 退款失败
+)
+
+// Valid 是否为 已定义的 订单状态
+func (status OrderStatus) Valid() bool {
+	switch status {
+	case OrderStatusUnpaid, OrderStatusPaid, OrderStatusCanceled, OrderStatusVerified,
+		OrderStatusRefunding, OrderStatusRefunded, OrderStatusRefundFailed:
+		return true
+	}
+	return false
+}
+
+// OrderItem 订单中的 商品
+type OrderItem struct {
+	ItemCode string `json:"item_code"`           // 开发者侧的 商品 id
+	Img      string `json:"img"`                 // 商品图片 url
+	Title    string `json:"title"`               // 商品名称
+	SubTitle string `json:"sub_title,omitempty"` // 商品副标题
+	Amount   int64  `json:"amount"`              // 商品数量
+	Price    int64  `json:"price"`               // 商品单价，单位为 分
+}
+
+// Validate 校验必填参数
+func (item OrderItem) Validate() error {
+	switch {
+	case item.ItemCode == "":
+		return errors.New("item_code is required")
+	case item.Img == "":
+		return errors.New("img is required")
+	case item.Title == "":
+		return errors.New("title is required")
+	case item.Amount <= 0:
+		return errors.New("amount must be positive")
+	case item.Price < 0:
+		return errors.New("price must not be negative")
+	}
+	return nil
+}
+
+// OrderDetail 订单详情，同步时 编码为 JSON 字符串
+type OrderDetail struct {
+	OrderId    string      `json:"order_id"`    // 开发者侧的 订单号
+	CreateTime int64       `json:"create_time"` // 下单时间，unix 时间戳，单位 毫秒
+	Status     string      `json:"status"`      // 订单状态 的 展示文案，如 待支付 已支付
+	Amount     int64       `json:"amount"`      // 商品总数量
+	TotalPrice int64       `json:"total_price"` // 订单总价，单位为 分
+	DetailUrl  string      `json:"detail_url"`  // 小程序 订单详情页 路径
+	ItemList   []OrderItem `json:"item_list"`   // 商品列表
+}
+
+// Validate 校验必填参数
+func (detail OrderDetail) Validate() error {
+	switch {
+	case detail.OrderId == "":
+		return errors.New("order_detail.order_id is required")
+	case detail.CreateTime == 0:
+		return errors.New("order_detail.create_time is required")
+	case detail.Status == "":
+		return errors.New("order_detail.status is required")
+	case detail.Amount <= 0:
+		return errors.New("order_detail.amount must be positive")
+	case detail.TotalPrice < 0:
+		return errors.New("order_detail.total_price must not be negative")
+	case detail.DetailUrl == "":
+		return errors.New("order_detail.detail_url is required")
+	case len(detail.ItemList) == 0:
+		return errors.New("order_detail.item_list is required")
+	}
+
+	for i, item := range detail.ItemList {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("order_detail.item_list[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+/*
+PushRequest 订单同步 请求参数
+
+编码为 JSON 时 OrderDetail 自动编码为 JSON 字符串，解码时 同时支持 字符串 和 对象
+*/
+type PushRequest struct {
+	AppName     string      `json:"app_name"`        // 宿主 app 名称 douyin douyinlite
+	OpenId      string      `json:"open_id"`         // 用户 openid
+	OrderDetail OrderDetail `json:"order_detail"`    // 订单详情
+	OrderStatus OrderStatus `json:"order_status"`    // 订单状态
+	OrderType   int64       `json:"order_type"`      // 订单类型 0 普通小程序订单
+	UpdateTime  int64       `json:"update_time"`     // 订单状态更新时间，unix 时间戳，单位 毫秒
+	Extra       string      `json:"extra,omitempty"` // 自定义字段
+}
+
+// Validate 校验必填参数
+func (req PushRequest) Validate() error {
+	switch {
+	case req.AppName == "":
+		return errors.New("app_name is required")
+	case req.OpenId == "":
+		return errors.New("open_id is required")
+	case !req.OrderStatus.Valid():
+		return fmt.Errorf("invalid order_status %d", req.OrderStatus)
+	case req.UpdateTime == 0:
+		return errors.New("update_time is required")
+	}
+	return req.OrderDetail.Validate()
+}
+
+// pushRequest 请求参数 的 JSON 结构，order_detail 为 JSON 字符串
+type pushRequest PushRequest
+
+func (req PushRequest) payload(accessToken string) ([]byte, error) {
+	detail, err := json.Marshal(req.OrderDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		AccessToken string `json:"access_token,omitempty"`
+		pushRequest
+		OrderDetail string `json:"order_detail"`
+	}{accessToken, pushRequest(req), string(detail)})
+}
+
+func (req PushRequest) MarshalJSON() ([]byte, error) {
+	return req.payload("")
+}
+
+func (req *PushRequest) UnmarshalJSON(data []byte) (err error) {
+	wire := struct {
+		*pushRequest
+		OrderDetail json.RawMessage `json:"order_detail"`
+	}{pushRequest: (*pushRequest)(req)}
+	if err = json.Unmarshal(data, &wire); err != nil {
+		return
+	}
+
+	detail := []byte(wire.OrderDetail)
+	if len(detail) > 0 && detail[0] == '"' {
+		var s string
+		if err = json.Unmarshal(detail, &s); err != nil {
+			return
+		}
+		detail = []byte(s)
+	}
+	if len(detail) == 0 || string(detail) == "null" {
+		return
+	}
+	return json.Unmarshal(detail, &req.OrderDetail)
+}
+
+// PushResponse 订单同步 响应
+type PushResponse struct {
+	ErrCode int64  `json:"err_code"` // 错误码
+	ErrMsg  string `json:"err_msg"`  // 错误信息
+	Body    string `json:"body"`     // 响应内容
+}
+
+/*
+订单同步
+
+将 小程序订单 同步到 抖音 订单中心，订单状态变化时 需要再次同步；调用前校验必填参数，自动填充 access_token，err_code 不为 0 时 返回错误
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/order/order-sync
+
+POST https://developer.toutiao.com/api/apps/order/v2/push
+*/
+func Push(ctx *microapp.MicroApp, req PushRequest) (resp PushResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	accessToken, err := ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := req.payload(accessToken)
+	if err != nil {
+		return
+	}
+
+	raw, err := ctx.Client.HTTPPost(apiPush, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(raw, &resp); err != nil {
+		return
+	}
+	if resp.ErrCode != 0 {
+		err = fmt.Errorf("err_code %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return
+}
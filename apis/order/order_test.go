@@ -0,0 +1,132 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package order
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func testRequest() PushRequest {
+	return PushRequest{
+		AppName:     "douyin",
+		OpenId:      "OPENID",
+		OrderStatus: OrderStatusPaid,
+		UpdateTime:  1602507471000,
+		OrderDetail: OrderDetail{
+			OrderId:    "ORDER_1",
+			CreateTime: 1602507400000,
+			Status:     "已支付",
+			Amount:     2,
+			TotalPrice: 200,
+			DetailUrl:  "pages/order?id=ORDER_1",
+			ItemList: []OrderItem{
+				{ItemCode: "ITEM_1", Img: "https://example.com/1.png", Title: "商品", Amount: 2, Price: 100},
+			},
+		},
+	}
+}
+
+func TestPush(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	resp, err := Push(env.MicroApp, testRequest())
+	if err != nil || resp.ErrCode != 0 {
+		t.Fatalf("Push() = %+v, %v", resp, err)
+	}
+
+	env.Capture.AssertAccessToken(t, apiPush, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiPush, "order_status", float64(OrderStatusPaid))
+
+	detail, err := env.Capture.MustLastRequest(t, apiPush).JSONField("order_detail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := detail.(string)
+	if !ok {
+		t.Fatalf("order_detail = %#v, want JSON string", detail)
+	}
+	var decoded OrderDetail
+	if err = json.Unmarshal([]byte(s), &decoded); err != nil || decoded.OrderId != "ORDER_1" || decoded.ItemList[0].Price != 100 {
+		t.Errorf("order_detail = %s, %v", s, err)
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	tests := []struct {
+		name   string
+		modify func(req *PushRequest)
+		want   string
+	}{
+		{"open_id", func(req *PushRequest) { req.OpenId = "" }, "open_id is required"},
+		{"order_status", func(req *PushRequest) { req.OrderStatus = 3 }, "invalid order_status 3"},
+		{"detail_url", func(req *PushRequest) { req.OrderDetail.DetailUrl = "" }, "order_detail.detail_url is required"},
+		{"item_list", func(req *PushRequest) { req.OrderDetail.ItemList = nil }, "order_detail.item_list is required"},
+		{"item", func(req *PushRequest) { req.OrderDetail.ItemList[0].Title = "" }, "order_detail.item_list[0]: title is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testRequest()
+			tt.modify(&req)
+			if _, err := Push(env.MicroApp, req); err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+	if _, ok := env.Capture.LastRequest(apiPush); ok {
+		t.Error("invalid requests should not be sent")
+	}
+
+	env.Mux.HandleFunc(apiPush, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"err_code":10001,"err_msg":"open_id invalid","body":""}`))
+	})
+	resp, err := Push(env.MicroApp, testRequest())
+	if err == nil || !strings.Contains(err.Error(), "open_id invalid") || resp.ErrCode != 10001 {
+		t.Errorf("Push() = %+v, %v, want err_code error", resp, err)
+	}
+}
+
+func TestPushRequestJSON(t *testing.T) {
+	data, err := json.Marshal(testRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "access_token") || !strings.Contains(string(data), `"order_detail":"{\"order_id\":\"ORDER_1\"`) {
+		t.Errorf("json = %s", data)
+	}
+
+	var req PushRequest
+	if err = json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.OpenId != "OPENID" || req.OrderDetail.OrderId != "ORDER_1" || len(req.OrderDetail.ItemList) != 1 {
+		t.Errorf("decoded = %+v", req)
+	}
+
+	// order_detail 为 对象 时 也可以解码
+	req = PushRequest{}
+	if err = json.Unmarshal([]byte(`{"open_id":"OPENID","order_detail":{"order_id":"ORDER_2"}}`), &req); err != nil || req.OrderDetail.OrderId != "ORDER_2" {
+		t.Errorf("decoded = %+v, %v", req, err)
+	}
+}
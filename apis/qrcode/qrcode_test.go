@@ -15,7 +15,6 @@
 package qrcode
 
 import (
-	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -30,13 +29,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestCreateQRCode(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiCreateQRCode, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiCreateQRCode)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -48,18 +41,18 @@ func TestCreateQRCode(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := CreateQRCode(tt.args.ctx, tt.args.payload)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateQRCode() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("CreateQRCode() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
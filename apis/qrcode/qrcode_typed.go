@@ -0,0 +1,74 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qrcode
+
+import (
+	"encoding/json"
+
+	"github.com/fastwego/microapp"
+)
+
+// Color 二维码线条颜色，默认为黑色
+type Color struct {
+	R int `json:"r,omitempty"`
+	G int `json:"g,omitempty"`
+	B int `json:"b,omitempty"`
+}
+
+// CreateQRCodeRequest createQRCode 请求参数
+type CreateQRCodeRequest struct {
+	Appname    string `json:"appname,omitempty"`    // 是打开二维码的字节系 app 名称，默认为今日头条 toutiao douyin pipixia huoshan
+	Path       string `json:"path,omitempty"`       // 小程序/小游戏启动参数，小程序则格式为 encode({path}?{query})
+	Width      int    `json:"width,omitempty"`      // 二维码宽度，单位 px，最小 280px，最大 1280px，默认为 430px
+	LineColor  *Color `json:"line_color,omitempty"` // 二维码线条颜色，默认为黑色
+	Background *Color `json:"background,omitempty"` // 二维码背景颜色，默认为白色
+	SetIcon    bool   `json:"set_icon,omitempty"`   // 是否展示小程序/小游戏 icon，默认不展示
+}
+
+// Validate 校验必填参数
+func (req CreateQRCodeRequest) Validate() error {
+	return nil
+}
+
+/*
+createQRCode
+
+使用结构体 作为请求参数和响应 的 CreateQRCode，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/qr-code/create-qr-code
+
+POST https://developer.toutiao.com/api/apps/qrcode
+*/
+func CreateQRCodeTyped(ctx *microapp.MicroApp, req CreateQRCodeRequest) (resp []byte, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		CreateQRCodeRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	return CreateQRCode(ctx, payload)
+}
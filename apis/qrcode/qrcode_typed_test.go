@@ -0,0 +1,48 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qrcode
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestCreateQRCodeTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     CreateQRCodeRequest
+		wantErr bool
+	}{
+		{name: "case1", req: CreateQRCodeRequest{Appname: "test"}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := CreateQRCodeTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateQRCodeTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(resp) != "{\"errcode\":0,\"errmsg\":\"ok\"}" {
+				t.Errorf("resp = %s, want %s", resp, "{\"errcode\":0,\"errmsg\":\"ok\"}")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiCreateQRCode, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiCreateQRCode, "appname", "test")
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share_test
+
+import (
+	"fmt"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/share"
+)
+
+func ExampleListTemplate() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := share.ListTemplate(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleCreateTemplate() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := share.CreateTemplate(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleQueryShareData() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := share.QueryShareData(ctx, payload)
+
+	fmt.Println(resp, err)
+}
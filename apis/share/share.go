@@ -0,0 +1,67 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package share 分享
+package share
+
+import (
+	"bytes"
+
+	"github.com/fastwego/microapp"
+)
+
+const (
+	apiListTemplate   = "/api/apps/share/template/list"
+	apiCreateTemplate = "/api/apps/share/template/create"
+	apiQueryShareData = "/api/apps/share/data"
+)
+
+/*
+查询分享模板列表
+
+分页查询 小程序 已创建的 分享模板
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-list
+
+POST https://developer.toutiao.com/api/apps/share/template/list
+*/
+func ListTemplate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiListTemplate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+创建分享模板
+
+创建 分享模板，审核通过后 可在 分享时 通过 template_id 使用
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-create
+
+POST https://developer.toutiao.com/api/apps/share/template/create
+*/
+func CreateTemplate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiCreateTemplate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+查询分享数据
+
+按天查询 分享 和 分享回流 的统计数据，时间范围 最多 30 天
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/share-data
+
+POST https://developer.toutiao.com/api/apps/share/data
+*/
+func QueryShareData(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiQueryShareData, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
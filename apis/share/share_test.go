@@ -0,0 +1,120 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestListTemplate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiListTemplate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := ListTemplate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("ListTemplate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestCreateTemplate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiCreateTemplate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := CreateTemplate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("CreateTemplate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestQueryShareData(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiQueryShareData)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := QueryShareData(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryShareData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("QueryShareData() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
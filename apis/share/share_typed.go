@@ -0,0 +1,257 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// ListTemplateRequest 查询分享模板列表 请求参数
+type ListTemplateRequest struct {
+	PageNum  int `json:"page_num"`         // 页码，从 1 开始
+	PageSize int `json:"page_size"`        // 每页数量，最大 50
+	Status   int `json:"status,omitempty"` // 按审核状态筛选 1 审核中 2 通过 3 拒绝，不填 返回全部
+}
+
+// Validate 校验必填参数
+func (req ListTemplateRequest) Validate() error {
+	if req.PageNum == 0 {
+		return errors.New("page_num is required")
+	}
+
+	if req.PageSize == 0 {
+		return errors.New("page_size is required")
+	}
+
+	return nil
+}
+
+// ShareTemplate 分享模板
+type ShareTemplate struct {
+	TemplateId string `json:"template_id"` // 分享模板 id
+	Title      string `json:"title"`       // 分享标题
+	Desc       string `json:"desc"`        // 分享描述
+	ImageUrl   string `json:"image_url"`   // 分享图片链接
+	Path       string `json:"path"`        // 分享打开的页面路径
+	Query      string `json:"query"`       // 页面参数
+	Status     int    `json:"status"`      // 审核状态 1 审核中 2 通过 3 拒绝
+	CreateTime int64  `json:"create_time"` // 创建时间，unix 时间戳
+}
+
+// TemplateList 分享模板列表
+type TemplateList struct {
+	Total     int64           `json:"total"`     // 模板总数
+	Templates []ShareTemplate `json:"templates"` // 分享模板
+}
+
+// ListTemplateResponse 查询分享模板列表 响应
+type ListTemplateResponse struct {
+	ErrNo   int64         `json:"err_no"`   // 错误码
+	ErrTips string        `json:"err_tips"` // 错误信息
+	Data    *TemplateList `json:"data"`     // 分享模板列表
+}
+
+// CreateTemplateRequest 创建分享模板 请求参数
+type CreateTemplateRequest struct {
+	Title    string `json:"title"`           // 分享标题，最多 30 个字符
+	Desc     string `json:"desc,omitempty"`  // 分享描述
+	ImageUrl string `json:"image_url"`       // 分享图片链接，宽高比 5:4
+	Path     string `json:"path"`            // 分享打开的页面路径
+	Query    string `json:"query,omitempty"` // 页面参数，JSON 字符串
+}
+
+// Validate 校验必填参数
+func (req CreateTemplateRequest) Validate() error {
+	if req.Title == "" {
+		return errors.New("title is required")
+	}
+
+	if req.ImageUrl == "" {
+		return errors.New("image_url is required")
+	}
+
+	if req.Path == "" {
+		return errors.New("path is required")
+	}
+
+	return nil
+}
+
+// CreateTemplateResult 创建结果
+type CreateTemplateResult struct {
+	TemplateId string `json:"template_id"` // 分享模板 id
+}
+
+// CreateTemplateResponse 创建分享模板 响应
+type CreateTemplateResponse struct {
+	ErrNo   int64                 `json:"err_no"`   // 错误码
+	ErrTips string                `json:"err_tips"` // 错误信息
+	Data    *CreateTemplateResult `json:"data"`     // 创建结果
+}
+
+// QueryShareDataRequest 查询分享数据 请求参数
+type QueryShareDataRequest struct {
+	StartDate  string `json:"start_date"`            // 开始日期，格式 2006-01-02
+	EndDate    string `json:"end_date"`              // 结束日期，格式 2006-01-02
+	TemplateId string `json:"template_id,omitempty"` // 分享模板 id，不填 统计全部分享
+}
+
+// Validate 校验必填参数
+func (req QueryShareDataRequest) Validate() error {
+	if req.StartDate == "" {
+		return errors.New("start_date is required")
+	}
+
+	if req.EndDate == "" {
+		return errors.New("end_date is required")
+	}
+
+	return nil
+}
+
+// ShareDailyData 每天的统计数据
+type ShareDailyData struct {
+	Date           string `json:"date"`             // 日期
+	ShareCount     int64  `json:"share_count"`      // 分享次数
+	ShareUserCount int64  `json:"share_user_count"` // 分享人数
+	ClickCount     int64  `json:"click_count"`      // 分享回流次数
+	ClickUserCount int64  `json:"click_user_count"` // 分享回流人数
+}
+
+// ShareData 分享数据
+type ShareData struct {
+	List []ShareDailyData `json:"list"` // 每天的统计数据
+}
+
+// QueryShareDataResponse 查询分享数据 响应
+type QueryShareDataResponse struct {
+	ErrNo   int64      `json:"err_no"`   // 错误码
+	ErrTips string     `json:"err_tips"` // 错误信息
+	Data    *ShareData `json:"data"`     // 分享数据
+}
+
+/*
+查询分享模板列表
+
+使用结构体 作为请求参数和响应 的 ListTemplate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-list
+
+POST https://developer.toutiao.com/api/apps/share/template/list
+*/
+func ListTemplateTyped(ctx *microapp.MicroApp, req ListTemplateRequest) (resp ListTemplateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		ListTemplateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := ListTemplate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+创建分享模板
+
+使用结构体 作为请求参数和响应 的 CreateTemplate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-create
+
+POST https://developer.toutiao.com/api/apps/share/template/create
+*/
+func CreateTemplateTyped(ctx *microapp.MicroApp, req CreateTemplateRequest) (resp CreateTemplateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		CreateTemplateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := CreateTemplate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询分享数据
+
+使用结构体 作为请求参数和响应 的 QueryShareData，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/share-data
+
+POST https://developer.toutiao.com/api/apps/share/data
+*/
+func QueryShareDataTyped(ctx *microapp.MicroApp, req QueryShareDataRequest) (resp QueryShareDataResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		QueryShareDataRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := QueryShareData(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
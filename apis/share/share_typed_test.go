@@ -0,0 +1,103 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestListTemplateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     ListTemplateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: ListTemplateRequest{PageNum: 1, PageSize: 1}, wantErr: false},
+		{name: "required", req: ListTemplateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ListTemplateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListTemplateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiListTemplate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiListTemplate, "page_num", 1)
+}
+
+func TestCreateTemplateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     CreateTemplateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: CreateTemplateRequest{Title: "test", ImageUrl: "test", Path: "test"}, wantErr: false},
+		{name: "required", req: CreateTemplateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := CreateTemplateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTemplateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiCreateTemplate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiCreateTemplate, "title", "test")
+}
+
+func TestQueryShareDataTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     QueryShareDataRequest
+		wantErr bool
+	}{
+		{name: "case1", req: QueryShareDataRequest{StartDate: "test", EndDate: "test"}, wantErr: false},
+		{name: "required", req: QueryShareDataRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := QueryShareDataTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryShareDataTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiQueryShareData, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiQueryShareData, "start_date", "test")
+}
@@ -29,3 +29,48 @@ func ExampleNotify() {
 
 	fmt.Println(resp, err)
 }
+
+func ExampleListLibrary() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := subscribe_notification.ListLibrary(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleListLibraryKeywords() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := subscribe_notification.ListLibraryKeywords(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleListTemplate() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := subscribe_notification.ListTemplate(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleCreateTemplate() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := subscribe_notification.CreateTemplate(ctx, payload)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleDeleteTemplate() {
+	var ctx *microapp.MicroApp
+
+	payload := []byte("{}")
+	resp, err := subscribe_notification.DeleteTemplate(ctx, payload)
+
+	fmt.Println(resp, err)
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification_test
+
+import (
+	"fmt"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/subscribe_notification"
+)
+
+func ExampleNotifyTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.NotifyRequest{}
+	resp, err := subscribe_notification.NotifyTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleListLibraryTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.ListLibraryRequest{}
+	resp, err := subscribe_notification.ListLibraryTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleListLibraryKeywordsTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.ListLibraryKeywordsRequest{}
+	resp, err := subscribe_notification.ListLibraryKeywordsTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleListTemplateTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.ListTemplateRequest{}
+	resp, err := subscribe_notification.ListTemplateTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleCreateTemplateTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.CreateTemplateRequest{}
+	resp, err := subscribe_notification.CreateTemplateTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
+
+func ExampleDeleteTemplateTyped() {
+	var ctx *microapp.MicroApp
+
+	req := subscribe_notification.DeleteTemplateRequest{}
+	resp, err := subscribe_notification.DeleteTemplateTyped(ctx, req)
+
+	fmt.Println(resp, err)
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fastwego/microapp"
+)
+
+// 分页查询 小程序模板 时 每页的数量
+const templatePageSize = 50
+
+var ErrorTemplateNotFound = errors.New("template not registered")
+
+/*
+TemplateDataError 推送内容 与 模板关键词 不匹配
+*/
+type TemplateDataError struct {
+	TplId   string
+	Missing []string // 缺少 或 内容为空 的关键词
+	Unknown []string // 模板中 不存在的 关键词
+}
+
+func (e *TemplateDataError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ","))
+	}
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown "+strings.Join(e.Unknown, ","))
+	}
+	return fmt.Sprintf("template %s data: %s", e.TplId, strings.Join(problems, "; "))
+}
+
+/*
+TemplateRegistry 本地 模板注册表
+
+推送前 按 模板关键词 校验 data，避免 关键词拼写错误 等问题 消耗 用户的订阅次数
+
+	registry := subscribe_notification.NewTemplateRegistry()
+	err := registry.Load(ctx)
+	resp, err := registry.Notify(ctx, subscribe_notification.NotifyRequest{TplId: tplId, OpenId: openId, Data: data})
+*/
+type TemplateRegistry struct {
+	mutex     sync.RWMutex
+	templates map[string]AppTemplate
+}
+
+/*
+创建 模板注册表
+*/
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{templates: map[string]AppTemplate{}}
+}
+
+// Register 注册 模板，tpl_id 相同时 覆盖
+func (registry *TemplateRegistry) Register(templates ...AppTemplate) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, template := range templates {
+		registry.templates[template.TplId] = template
+	}
+}
+
+// Lookup 查找 已注册的 模板
+func (registry *TemplateRegistry) Lookup(tplId string) (template AppTemplate, ok bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	template, ok = registry.templates[tplId]
+	return
+}
+
+/*
+Load 分页查询 小程序的 全部模板，替换 注册表中的模板
+
+查询失败时 注册表 保持不变
+*/
+func (registry *TemplateRegistry) Load(ctx *microapp.MicroApp) (err error) {
+	templates := map[string]AppTemplate{}
+	for pageNum := int64(1); ; pageNum++ {
+		resp, err := ListTemplateTyped(ctx, ListTemplateRequest{AppId: ctx.Config.AppId, PageNum: pageNum, PageSize: templatePageSize})
+		if err != nil {
+			return err
+		}
+		if resp.ErrNo != 0 {
+			return fmt.Errorf("err_no %d: %s", resp.ErrNo, resp.ErrTips)
+		}
+
+		for _, template := range resp.TemplateList {
+			templates[template.TplId] = template
+		}
+		// total 可能 缺失 或 为 0，只在 total 有效时 使用；不足一页 说明 已是最后一页
+		if len(resp.TemplateList) < templatePageSize || (resp.Total > 0 && pageNum*templatePageSize >= resp.Total) {
+			break
+		}
+	}
+
+	registry.mutex.Lock()
+	registry.templates = templates
+	registry.mutex.Unlock()
+	return
+}
+
+/*
+Validate 校验 推送内容
+
+模板未注册 时 返回 ErrorTemplateNotFound；data 缺少关键词、关键词内容为空 或 包含模板中不存在的关键词 时 返回 *TemplateDataError
+*/
+func (registry *TemplateRegistry) Validate(req NotifyRequest) error {
+	template, ok := registry.Lookup(req.TplId)
+	if !ok {
+		return ErrorTemplateNotFound
+	}
+
+	dataError := &TemplateDataError{TplId: req.TplId}
+	keywords := map[string]bool{}
+	for _, keyword := range template.KeywordList {
+		keywords[keyword.Name] = true
+		if strings.TrimSpace(req.Data[keyword.Name]) == "" {
+			dataError.Missing = append(dataError.Missing, keyword.Name)
+		}
+	}
+	for name := range req.Data {
+		if !keywords[name] {
+			dataError.Unknown = append(dataError.Unknown, name)
+		}
+	}
+	sort.Strings(dataError.Unknown)
+
+	if len(dataError.Missing) > 0 || len(dataError.Unknown) > 0 {
+		return dataError
+	}
+	return nil
+}
+
+/*
+Notify 校验 推送内容 后 推送订阅消息
+
+AppId 为空时 使用 ctx.Config.AppId；err_no 不为 0 时 返回 *NotifyError
+*/
+func (registry *TemplateRegistry) Notify(ctx *microapp.MicroApp, req NotifyRequest) (resp NotifyResponse, err error) {
+	if req.AppId == "" {
+		req.AppId = ctx.Config.AppId
+	}
+	if err = registry.Validate(req); err != nil {
+		return
+	}
+
+	resp, err = NotifyTyped(ctx, req)
+	if err == nil && resp.ErrNo != ErrNoSuccess {
+		err = &NotifyError{ErrNo: resp.ErrNo, ErrTips: resp.ErrTips}
+	}
+	return
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestTemplateRegistryLoad(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	// 共 51 个模板，分 2 页返回
+	env.Mux.HandleFunc(apiListTemplate, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := ListTemplateRequest{}
+		_ = json.Unmarshal(body, &req)
+
+		resp := ListTemplateResponse{Total: 51}
+		for i := (req.PageNum - 1) * req.PageSize; i < req.PageNum*req.PageSize && i < resp.Total; i++ {
+			resp.TemplateList = append(resp.TemplateList, AppTemplate{
+				TplId:       fmt.Sprintf("TPL_%d", i),
+				KeywordList: []Keyword{{Name: "物品名称"}, {Name: "金额"}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	registry := NewTemplateRegistry()
+	registry.Register(AppTemplate{TplId: "REMOVED"})
+	if err := registry.Load(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := registry.Lookup("TPL_50"); !ok {
+		t.Error("templates on the second page should be registered")
+	}
+	if _, ok := registry.Lookup("REMOVED"); ok {
+		t.Error("Load should replace registered templates")
+	}
+	env.Capture.AssertJSONField(t, apiListTemplate, "page_num", float64(2))
+	env.Capture.AssertJSONField(t, apiListTemplate, "app_id", "APPID")
+}
+
+func TestTemplateRegistryLoadWithoutTotal(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	// 共 120 个模板，分 3 页返回，响应中 没有 total
+	var pages []int64
+	env.Mux.HandleFunc(apiListTemplate, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := ListTemplateRequest{}
+		_ = json.Unmarshal(body, &req)
+		pages = append(pages, req.PageNum)
+
+		var list []AppTemplate
+		for i := (req.PageNum - 1) * req.PageSize; i < req.PageNum*req.PageSize && i < 120; i++ {
+			list = append(list, AppTemplate{TplId: fmt.Sprintf("TPL_%d", i)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"err_no": 0, "template_list": list})
+	})
+
+	registry := NewTemplateRegistry()
+	if err := registry.Load(env.MicroApp); err != nil {
+		t.Fatal(err)
+	}
+	for _, tplId := range []string{"TPL_0", "TPL_50", "TPL_119"} {
+		if _, ok := registry.Lookup(tplId); !ok {
+			t.Errorf("%s not registered", tplId)
+		}
+	}
+	if !reflect.DeepEqual(pages, []int64{1, 2, 3}) {
+		t.Errorf("pages = %v, want 1 2 3", pages)
+	}
+}
+
+func TestTemplateRegistryNotify(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	registry := NewTemplateRegistry()
+	registry.Register(AppTemplate{TplId: "TPL_1", KeywordList: []Keyword{{Name: "物品名称"}, {Name: "金额"}}})
+
+	tests := []struct {
+		name    string
+		req     NotifyRequest
+		wantErr error
+	}{
+		{
+			name:    "not registered",
+			req:     NotifyRequest{TplId: "TPL_2", OpenId: "OPENID", Data: map[string]string{"物品名称": "咖啡"}},
+			wantErr: ErrorTemplateNotFound,
+		},
+		{
+			name:    "missing and unknown",
+			req:     NotifyRequest{TplId: "TPL_1", OpenId: "OPENID", Data: map[string]string{"物品名称": "咖啡", "价格": "1"}},
+			wantErr: &TemplateDataError{TplId: "TPL_1", Missing: []string{"金额"}, Unknown: []string{"价格"}},
+		},
+		{
+			name:    "empty value",
+			req:     NotifyRequest{TplId: "TPL_1", OpenId: "OPENID", Data: map[string]string{"物品名称": " ", "金额": "1"}},
+			wantErr: &TemplateDataError{TplId: "TPL_1", Missing: []string{"物品名称"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := registry.Notify(env.MicroApp, tt.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, ok := env.Capture.LastRequest(apiNotify); ok {
+		t.Fatal("invalid data should not be sent")
+	}
+
+	resp, err := registry.Notify(env.MicroApp, NotifyRequest{TplId: "TPL_1", OpenId: "OPENID", Data: map[string]string{"物品名称": "咖啡", "金额": "1"}})
+	if err != nil || resp.ErrNo != 0 {
+		t.Fatalf("Notify() = %+v, %v", resp, err)
+	}
+	env.Capture.AssertJSONField(t, apiNotify, "app_id", "APPID")
+	env.Capture.AssertAccessToken(t, apiNotify, env.MicroApp)
+}
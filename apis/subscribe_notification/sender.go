@@ -0,0 +1,256 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fastwego/microapp"
+)
+
+// 推送 错误码 err_no，其他错误码 见 err_tips
+const (
+	ErrNoSuccess       = 0
+	ErrNoParam         = 1 // 参数错误，如 模板不存在、data 与模板不匹配
+	ErrNoSystem        = 2 // 服务内部错误，可稍后重试
+	ErrNoFrequency     = 3 // 调用频率超限，可稍后重试
+	ErrNoNotSubscribed = 4 // 用户未订阅 或 订阅次数已用完
+)
+
+/*
+NotifyError 推送 接口错误，err_no 不为 0
+*/
+type NotifyError struct {
+	ErrNo   int64  `json:"err_no"`
+	ErrTips string `json:"err_tips"`
+}
+
+func (e *NotifyError) Error() string {
+	return fmt.Sprintf("notify err_no %d: %s", e.ErrNo, e.ErrTips)
+}
+
+// Temporary 服务内部错误 或 调用频率超限，可稍后重试
+func (e *NotifyError) Temporary() bool {
+	return e.ErrNo == ErrNoSystem || e.ErrNo == ErrNoFrequency
+}
+
+/*
+SendResult 单个用户的 推送结果
+*/
+type SendResult struct {
+	OpenId    string
+	Resp      NotifyResponse
+	Err       error // 推送失败的原因，err_no 不为 0 时 为 *NotifyError
+	Attempts  int   // 请求次数，包括 重试
+	Permanent bool  // 重试也无法成功的错误，如 用户未订阅、参数错误
+}
+
+/*
+BulkSender 向 多个用户 推送 相同内容的 订阅消息
+
+使用 Workers 个 goroutine 并发推送，所有请求 共享 QPS 限制；
+网络错误、系统繁忙、HTTP 5xx、服务内部错误、调用频率超限 等 临时错误 最多重试 Retries 次，用户未订阅、HTTP 4xx 等 永久错误 不重试
+
+零值 BulkSender 可直接使用，未设置的字段 使用 默认值
+
+	sender := subscribe_notification.NewBulkSender()
+	sender.Registry = registry
+	results := sender.Send(context.Background(), ctx, subscribe_notification.NotifyRequest{TplId: tplId, Data: data}, openIds)
+*/
+type BulkSender struct {
+	Workers  int               // 并发数，0 时 默认 10
+	QPS      int               // 每秒 最多请求数，0 不限制
+	Retries  int               // 临时错误 重试次数，0 时 默认 2，负数 不重试
+	Backoff  time.Duration     // 第 n 次重试前 等待 n * Backoff，0 时 默认 1 秒
+	Registry *TemplateRegistry // 不为空时 推送前 按模板关键词 校验 data
+}
+
+const (
+	defaultWorkers = 10
+	defaultRetries = 2
+	defaultBackoff = time.Second
+)
+
+/*
+创建 批量推送
+*/
+func NewBulkSender() *BulkSender {
+	return &BulkSender{
+		Workers: defaultWorkers,
+		Retries: defaultRetries,
+		Backoff: defaultBackoff,
+	}
+}
+
+// withDefaults 返回 未设置字段 使用默认值 的副本，不修改 sender
+func (sender *BulkSender) withDefaults() BulkSender {
+	s := *sender
+	if s.Workers <= 0 {
+		s.Workers = defaultWorkers
+	}
+	if s.Retries == 0 {
+		s.Retries = defaultRetries
+	} else if s.Retries < 0 {
+		s.Retries = 0
+	}
+	if s.Backoff <= 0 {
+		s.Backoff = defaultBackoff
+	}
+	return s
+}
+
+/*
+Send 向 openIds 推送 req，req.OpenId 会被替换；AppId 为空时 使用 ctx.Config.AppId
+
+返回的结果 与 openIds 一一对应；c 取消后 未推送的用户 Err 为 c.Err()
+*/
+func (sender *BulkSender) Send(c context.Context, ctx *microapp.MicroApp, req NotifyRequest, openIds []string) (results []SendResult) {
+	settings := sender.withDefaults()
+	sender = &settings
+
+	if req.AppId == "" {
+		req.AppId = ctx.Config.AppId
+	}
+
+	results = make([]SendResult, len(openIds))
+	for i, openId := range openIds {
+		results[i] = SendResult{OpenId: openId}
+	}
+
+	// 校验 与用户无关，失败时 所有用户 都不推送
+	check := req
+	check.OpenId = "-"
+	if err := sender.validate(check); err != nil {
+		for i := range results {
+			results[i].Err = err
+			results[i].Permanent = true
+		}
+		return
+	}
+
+	var limit <-chan time.Time
+	if sender.QPS > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(sender.QPS))
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < sender.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				recipient := req
+				recipient.OpenId = openIds[i]
+				sender.send(c, ctx, recipient, limit, &results[i])
+			}
+		}()
+	}
+
+	for i := range openIds {
+		if c.Err() != nil {
+			results[i].Err = c.Err()
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-c.Done():
+			results[i].Err = c.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return
+}
+
+func (sender *BulkSender) validate(req NotifyRequest) error {
+	if sender.Registry != nil {
+		return sender.Registry.Validate(req)
+	}
+	return req.Validate()
+}
+
+// send 推送给 单个用户，临时错误 时 重试
+func (sender *BulkSender) send(c context.Context, ctx *microapp.MicroApp, req NotifyRequest, limit <-chan time.Time, result *SendResult) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && !sleep(c, time.Duration(attempt)*sender.Backoff) {
+			result.Err = c.Err()
+			return
+		}
+		if limit != nil {
+			select {
+			case <-limit:
+			case <-c.Done():
+				result.Err = c.Err()
+				return
+			}
+		}
+
+		result.Attempts++
+		result.Resp, result.Err = NotifyTyped(ctx, req)
+		if result.Err == nil && result.Resp.ErrNo != ErrNoSuccess {
+			result.Err = &NotifyError{ErrNo: result.Resp.ErrNo, ErrTips: result.Resp.ErrTips}
+		}
+		if result.Err == nil {
+			return
+		}
+
+		result.Permanent = !temporary(result.Err)
+		if result.Permanent || attempt >= sender.Retries {
+			return
+		}
+	}
+}
+
+// temporary 推送接口的 错误码 按 NotifyError.Temporary 判断；网络错误、系统繁忙、HTTP 5xx 为 临时错误，
+// 其他错误（HTTP 4xx、errcode、响应解析失败 等） 重试也无法成功，均视为 永久错误
+func temporary(err error) bool {
+	if e, ok := err.(*NotifyError); ok {
+		return e.Temporary()
+	}
+	if err == microapp.ErrorSystemBusy {
+		return true
+	}
+
+	var statusError *microapp.StatusError
+	if errors.As(err, &statusError) {
+		return statusError.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// sleep 等待 d，c 取消时 返回 false
+func sleep(c context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-c.Done():
+		return false
+	}
+}
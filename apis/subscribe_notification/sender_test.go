@@ -0,0 +1,224 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestBulkSender(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	var (
+		mutex    sync.Mutex
+		calls    = map[string]int{}
+		inflight int32
+		peak     int32
+	)
+	env.Mux.HandleFunc(apiNotify, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		req := NotifyRequest{}
+		_ = json.Unmarshal(body, &req)
+		mutex.Lock()
+		calls[req.OpenId]++
+		call := calls[req.OpenId]
+		mutex.Unlock()
+
+		switch {
+		case req.OpenId == "UNSUBSCRIBED":
+			_, _ = fmt.Fprintf(w, `{"err_no":%d,"err_tips":"user not subscribed"}`, ErrNoNotSubscribed)
+		case req.OpenId == "BUSY" && call == 1:
+			_, _ = fmt.Fprintf(w, `{"err_no":%d,"err_tips":"system error"}`, ErrNoSystem)
+		case req.OpenId == "DOWN":
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+		case req.OpenId == "BAD_GATEWAY":
+			w.WriteHeader(http.StatusBadGateway)
+		case req.OpenId == "FORBIDDEN":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`{"err_no":0,"err_tips":"success"}`))
+		}
+	})
+
+	openIds := []string{"UNSUBSCRIBED", "BUSY", "DOWN", "BAD_GATEWAY", "FORBIDDEN"}
+	for i := 0; i < 6; i++ {
+		openIds = append(openIds, fmt.Sprintf("OPENID_%d", i))
+	}
+
+	sender := NewBulkSender()
+	sender.Workers = 3
+	sender.Backoff = time.Millisecond
+	results := sender.Send(context.Background(), env.MicroApp, NotifyRequest{TplId: "TPL_1", Data: map[string]string{"物品名称": "咖啡"}}, openIds)
+
+	if len(results) != len(openIds) {
+		t.Fatalf("results = %d, want %d", len(results), len(openIds))
+	}
+	for i, result := range results {
+		if result.OpenId != openIds[i] {
+			t.Errorf("results[%d].OpenId = %s, want input order", i, result.OpenId)
+		}
+	}
+
+	unsubscribed := results[0]
+	if e, ok := unsubscribed.Err.(*NotifyError); !ok || e.ErrNo != ErrNoNotSubscribed || !unsubscribed.Permanent || unsubscribed.Attempts != 1 {
+		t.Errorf("unsubscribed = %+v, want permanent without retry", unsubscribed)
+	}
+	if busy := results[1]; busy.Err != nil || busy.Attempts != 2 {
+		t.Errorf("busy = %+v, want success after retry", busy)
+	}
+	if down := results[2]; down.Err == nil || down.Permanent || down.Attempts != 1+sender.Retries {
+		t.Errorf("down = %+v, want temporary error after %d retries", down, sender.Retries)
+	}
+	if badGateway := results[3]; badGateway.Err == nil || badGateway.Permanent || badGateway.Attempts != 1+sender.Retries {
+		t.Errorf("bad gateway = %+v, want temporary error after %d retries", badGateway, sender.Retries)
+	}
+	if forbidden := results[4]; forbidden.Err == nil || !forbidden.Permanent || forbidden.Attempts != 1 {
+		t.Errorf("forbidden = %+v, want permanent without retry", forbidden)
+	}
+	for _, result := range results[5:] {
+		if result.Err != nil || result.Attempts != 1 {
+			t.Errorf("%s = %+v", result.OpenId, result)
+		}
+	}
+
+	if peak > int32(sender.Workers) {
+		t.Errorf("peak concurrency = %d, want <= %d", peak, sender.Workers)
+	}
+	env.Capture.AssertJSONField(t, apiNotify, "app_id", "APPID")
+}
+
+func TestBulkSenderZeroValue(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	sender := &BulkSender{}
+	results := sender.Send(context.Background(), env.MicroApp, NotifyRequest{TplId: "TPL_1", Data: map[string]string{"物品名称": "咖啡"}}, []string{"A", "B"})
+	for _, result := range results {
+		if result.Err != nil || result.Attempts != 1 {
+			t.Errorf("%s = %+v", result.OpenId, result)
+		}
+	}
+	if *sender != (BulkSender{}) {
+		t.Errorf("Send() modified sender = %+v", sender)
+	}
+
+	settings := sender.withDefaults()
+	if settings.Workers != 10 || settings.Retries != 2 || settings.Backoff != time.Second {
+		t.Errorf("withDefaults() = %+v", settings)
+	}
+	if settings := (&BulkSender{Retries: -1}).withDefaults(); settings.Retries != 0 {
+		t.Errorf("withDefaults() Retries = %d, want no retry", settings.Retries)
+	}
+}
+
+func TestTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "system", err: &NotifyError{ErrNo: ErrNoSystem}, want: true},
+		{name: "not subscribed", err: &NotifyError{ErrNo: ErrNoNotSubscribed}, want: false},
+		{name: "system busy", err: microapp.ErrorSystemBusy, want: true},
+		{name: "network", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		{name: "bad gateway", err: &microapp.StatusError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}, want: true},
+		{name: "service unavailable", err: &microapp.StatusError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}, want: true},
+		{name: "not found", err: &microapp.StatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, want: false},
+		{name: "errcode", err: errors.New(`{"errcode":40001,"errmsg":"invalid credential"}`), want: false},
+		{name: "json", err: json.Unmarshal([]byte("<html>"), &NotifyResponse{}), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := temporary(tt.err); got != tt.want {
+				t.Errorf("temporary(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkSenderQPS(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	sender := NewBulkSender()
+	sender.QPS = 100
+
+	start := time.Now()
+	results := sender.Send(context.Background(), env.MicroApp, NotifyRequest{TplId: "TPL_1", Data: map[string]string{"物品名称": "咖啡"}}, []string{"A", "B", "C", "D", "E"})
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("5 requests at 100 qps took %v", elapsed)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.OpenId, result.Err)
+		}
+	}
+}
+
+func TestBulkSenderInvalid(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	registry := NewTemplateRegistry()
+	registry.Register(AppTemplate{TplId: "TPL_1", KeywordList: []Keyword{{Name: "物品名称"}, {Name: "金额"}}})
+
+	sender := NewBulkSender()
+	sender.Registry = registry
+	results := sender.Send(context.Background(), env.MicroApp, NotifyRequest{TplId: "TPL_1", Data: map[string]string{"物品名称": "咖啡"}}, []string{"A", "B"})
+	for _, result := range results {
+		if _, ok := result.Err.(*TemplateDataError); !ok || !result.Permanent || result.Attempts != 0 {
+			t.Errorf("%s = %+v, want permanent TemplateDataError", result.OpenId, result)
+		}
+	}
+	if _, ok := env.Capture.LastRequest(apiNotify); ok {
+		t.Error("invalid data should not be sent")
+	}
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = NewBulkSender().Send(c, env.MicroApp, NotifyRequest{TplId: "TPL_1", Data: map[string]string{"物品名称": "咖啡"}}, []string{"A", "B"})
+	for _, result := range results {
+		if result.Err != context.Canceled || result.Attempts != 0 {
+			t.Errorf("%s = %+v, want canceled", result.OpenId, result)
+		}
+	}
+}
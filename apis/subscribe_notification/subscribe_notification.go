@@ -22,7 +22,12 @@ import (
 )
 
 const (
-	apiNotify = "/api/apps/subscribe_notification/developer/v1/notify"
+	apiNotify              = "/api/apps/subscribe_notification/developer/v1/notify"
+	apiListLibrary         = "/api/apps/subscribe_notification/developer/v1/template/library/list"
+	apiListLibraryKeywords = "/api/apps/subscribe_notification/developer/v1/template/library/keywords"
+	apiListTemplate        = "/api/apps/subscribe_notification/developer/v1/template/list"
+	apiCreateTemplate      = "/api/apps/subscribe_notification/developer/v1/template/create"
+	apiDeleteTemplate      = "/api/apps/subscribe_notification/developer/v1/template/delete"
 )
 
 /*
@@ -37,3 +42,68 @@ POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/
 func Notify(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
 	return ctx.Client.HTTPPost(apiNotify, bytes.NewReader(payload), "application/json;charset=utf-8")
 }
+
+/*
+查询模板库
+
+分页查询 平台的 订阅消息 公共模板库
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/library/list
+*/
+func ListLibrary(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiListLibrary, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+查询模板库关键词
+
+查询 公共模板 可选的关键词，创建模板时 从中选择
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library-keywords
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/library/keywords
+*/
+func ListLibraryKeywords(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiListLibraryKeywords, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+查询小程序模板
+
+分页查询 小程序已添加的 订阅消息模板 及其关键词
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/list
+*/
+func ListTemplate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiListTemplate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+创建小程序模板
+
+从 公共模板库 选择 模板 和 关键词，为小程序 创建 订阅消息模板
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/create-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/create
+*/
+func CreateTemplate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiCreateTemplate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+删除小程序模板
+
+删除 小程序的 订阅消息模板
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/delete-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/delete
+*/
+func DeleteTemplate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiDeleteTemplate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
@@ -15,7 +15,6 @@
 package subscribe_notification
 
 import (
-	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -30,13 +29,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestNotify(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiNotify, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiNotify)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -48,20 +41,170 @@ func TestNotify(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := Notify(tt.args.ctx, tt.args.payload)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("Notify() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
 }
+func TestListLibrary(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiListLibrary)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := ListLibrary(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListLibrary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("ListLibrary() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestListLibraryKeywords(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiListLibraryKeywords)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := ListLibraryKeywords(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListLibraryKeywords() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("ListLibraryKeywords() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestListTemplate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiListTemplate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := ListTemplate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("ListTemplate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestCreateTemplate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiCreateTemplate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := CreateTemplate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("CreateTemplate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestDeleteTemplate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiDeleteTemplate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := DeleteTemplate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("DeleteTemplate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// NotifyRequest 订阅消息推送 请求参数
+type NotifyRequest struct {
+	AppId  string            `json:"app_id"`         // 小程序的 id
+	TplId  string            `json:"tpl_id"`         // 模板的 id
+	OpenId string            `json:"open_id"`        // 接收消息目标用户的 open_id
+	Data   map[string]string `json:"data"`           // 模板内容，格式形如 { "key1": "value1", "key2": "value2" }
+	Page   string            `json:"page,omitempty"` // 跳转的页面
+}
+
+// Validate 校验必填参数
+func (req NotifyRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.TplId == "" {
+		return errors.New("tpl_id is required")
+	}
+
+	if req.OpenId == "" {
+		return errors.New("open_id is required")
+	}
+
+	if len(req.Data) == 0 {
+		return errors.New("data is required")
+	}
+
+	return nil
+}
+
+// NotifyResponse 订阅消息推送 响应
+type NotifyResponse struct {
+	ErrNo   int64  `json:"err_no"`   // 错误码
+	ErrTips string `json:"err_tips"` // 错误信息
+}
+
+// ListLibraryRequest 查询模板库 请求参数
+type ListLibraryRequest struct {
+	AppId    string `json:"app_id"`            // 小程序的 id
+	PageNum  int64  `json:"page_num"`          // 页码，从 1 开始
+	PageSize int64  `json:"page_size"`         // 每页数量，最大 50
+	Keyword  string `json:"keyword,omitempty"` // 按 模板标题 搜索
+}
+
+// Validate 校验必填参数
+func (req ListLibraryRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.PageNum == 0 {
+		return errors.New("page_num is required")
+	}
+
+	if req.PageSize == 0 {
+		return errors.New("page_size is required")
+	}
+
+	return nil
+}
+
+// LibraryTemplate 公共模板列表
+type LibraryTemplate struct {
+	TitleId string `json:"title_id"` // 公共模板 id
+	Title   string `json:"title"`    // 模板标题
+	Type    int64  `json:"type"`     // 模板类型 1 一次性订阅 2 长期订阅
+}
+
+// ListLibraryResponse 查询模板库 响应
+type ListLibraryResponse struct {
+	ErrNo        int64             `json:"err_no"`        // 错误码
+	ErrTips      string            `json:"err_tips"`      // 错误信息
+	Total        int64             `json:"total"`         // 模板总数
+	TemplateList []LibraryTemplate `json:"template_list"` // 公共模板列表
+}
+
+// ListLibraryKeywordsRequest 查询模板库关键词 请求参数
+type ListLibraryKeywordsRequest struct {
+	AppId   string `json:"app_id"`   // 小程序的 id
+	TitleId string `json:"title_id"` // 公共模板 id
+}
+
+// Validate 校验必填参数
+func (req ListLibraryKeywordsRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.TitleId == "" {
+		return errors.New("title_id is required")
+	}
+
+	return nil
+}
+
+// Keyword 关键词列表
+type Keyword struct {
+	Kid     string `json:"kid"`     // 关键词 id
+	Name    string `json:"name"`    // 关键词名称，推送时 作为 data 的 key
+	Example string `json:"example"` // 关键词示例
+	Rule    string `json:"rule"`    // 关键词内容规则
+}
+
+// ListLibraryKeywordsResponse 查询模板库关键词 响应
+type ListLibraryKeywordsResponse struct {
+	ErrNo       int64     `json:"err_no"`       // 错误码
+	ErrTips     string    `json:"err_tips"`     // 错误信息
+	KeywordList []Keyword `json:"keyword_list"` // 关键词列表
+}
+
+// ListTemplateRequest 查询小程序模板 请求参数
+type ListTemplateRequest struct {
+	AppId    string `json:"app_id"`    // 小程序的 id
+	PageNum  int64  `json:"page_num"`  // 页码，从 1 开始
+	PageSize int64  `json:"page_size"` // 每页数量，最大 50
+}
+
+// Validate 校验必填参数
+func (req ListTemplateRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.PageNum == 0 {
+		return errors.New("page_num is required")
+	}
+
+	if req.PageSize == 0 {
+		return errors.New("page_size is required")
+	}
+
+	return nil
+}
+
+// AppTemplate 模板列表
+type AppTemplate struct {
+	TplId       string    `json:"tpl_id"`       // 模板 id，推送时 使用
+	TitleId     string    `json:"title_id"`     // 公共模板 id
+	Title       string    `json:"title"`        // 模板标题
+	Type        int64     `json:"type"`         // 模板类型 1 一次性订阅 2 长期订阅
+	KeywordList []Keyword `json:"keyword_list"` // 关键词列表
+}
+
+// ListTemplateResponse 查询小程序模板 响应
+type ListTemplateResponse struct {
+	ErrNo        int64         `json:"err_no"`        // 错误码
+	ErrTips      string        `json:"err_tips"`      // 错误信息
+	Total        int64         `json:"total"`         // 模板总数
+	TemplateList []AppTemplate `json:"template_list"` // 模板列表
+}
+
+// CreateTemplateRequest 创建小程序模板 请求参数
+type CreateTemplateRequest struct {
+	AppId     string   `json:"app_id"`     // 小程序的 id
+	TitleId   string   `json:"title_id"`   // 公共模板 id
+	KidList   []string `json:"kid_list"`   // 选用的关键词 id，按 推送时的展示顺序 排列
+	SceneDesc string   `json:"scene_desc"` // 使用场景描述
+}
+
+// Validate 校验必填参数
+func (req CreateTemplateRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.TitleId == "" {
+		return errors.New("title_id is required")
+	}
+
+	if len(req.KidList) == 0 {
+		return errors.New("kid_list is required")
+	}
+
+	if req.SceneDesc == "" {
+		return errors.New("scene_desc is required")
+	}
+
+	return nil
+}
+
+// CreateTemplateResponse 创建小程序模板 响应
+type CreateTemplateResponse struct {
+	ErrNo   int64  `json:"err_no"`   // 错误码
+	ErrTips string `json:"err_tips"` // 错误信息
+	TplId   string `json:"tpl_id"`   // 模板 id
+}
+
+// DeleteTemplateRequest 删除小程序模板 请求参数
+type DeleteTemplateRequest struct {
+	AppId string `json:"app_id"` // 小程序的 id
+	TplId string `json:"tpl_id"` // 模板 id
+}
+
+// Validate 校验必填参数
+func (req DeleteTemplateRequest) Validate() error {
+	if req.AppId == "" {
+		return errors.New("app_id is required")
+	}
+
+	if req.TplId == "" {
+		return errors.New("tpl_id is required")
+	}
+
+	return nil
+}
+
+// DeleteTemplateResponse 删除小程序模板 响应
+type DeleteTemplateResponse struct {
+	ErrNo   int64  `json:"err_no"`   // 错误码
+	ErrTips string `json:"err_tips"` // 错误信息
+}
+
+/*
+订阅消息推送
+
+使用结构体 作为请求参数和响应 的 Notify，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/notify
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/notify
+*/
+func NotifyTyped(ctx *microapp.MicroApp, req NotifyRequest) (resp NotifyResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		NotifyRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := Notify(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询模板库
+
+使用结构体 作为请求参数和响应 的 ListLibrary，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/library/list
+*/
+func ListLibraryTyped(ctx *microapp.MicroApp, req ListLibraryRequest) (resp ListLibraryResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		ListLibraryRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := ListLibrary(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询模板库关键词
+
+使用结构体 作为请求参数和响应 的 ListLibraryKeywords，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library-keywords
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/library/keywords
+*/
+func ListLibraryKeywordsTyped(ctx *microapp.MicroApp, req ListLibraryKeywordsRequest) (resp ListLibraryKeywordsResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		ListLibraryKeywordsRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := ListLibraryKeywords(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询小程序模板
+
+使用结构体 作为请求参数和响应 的 ListTemplate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/list
+*/
+func ListTemplateTyped(ctx *microapp.MicroApp, req ListTemplateRequest) (resp ListTemplateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		ListTemplateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := ListTemplate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+创建小程序模板
+
+使用结构体 作为请求参数和响应 的 CreateTemplate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/create-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/create
+*/
+func CreateTemplateTyped(ctx *microapp.MicroApp, req CreateTemplateRequest) (resp CreateTemplateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		CreateTemplateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := CreateTemplate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+删除小程序模板
+
+使用结构体 作为请求参数和响应 的 DeleteTemplate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/delete-template
+
+POST https://developer.toutiao.com/api/apps/subscribe_notification/developer/v1/template/delete
+*/
+func DeleteTemplateTyped(ctx *microapp.MicroApp, req DeleteTemplateRequest) (resp DeleteTemplateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		DeleteTemplateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := DeleteTemplate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscribe_notification
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestNotifyTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     NotifyRequest
+		wantErr bool
+	}{
+		{name: "case1", req: NotifyRequest{AppId: "test", TplId: "test", OpenId: "test", Data: map[string]string{"test": "test"}}, wantErr: false},
+		{name: "required", req: NotifyRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NotifyTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NotifyTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiNotify, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiNotify, "app_id", "test")
+}
+
+func TestListLibraryTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     ListLibraryRequest
+		wantErr bool
+	}{
+		{name: "case1", req: ListLibraryRequest{AppId: "test", PageNum: 1, PageSize: 1}, wantErr: false},
+		{name: "required", req: ListLibraryRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ListLibraryTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListLibraryTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiListLibrary, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiListLibrary, "app_id", "test")
+}
+
+func TestListLibraryKeywordsTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     ListLibraryKeywordsRequest
+		wantErr bool
+	}{
+		{name: "case1", req: ListLibraryKeywordsRequest{AppId: "test", TitleId: "test"}, wantErr: false},
+		{name: "required", req: ListLibraryKeywordsRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ListLibraryKeywordsTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListLibraryKeywordsTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiListLibraryKeywords, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiListLibraryKeywords, "app_id", "test")
+}
+
+func TestListTemplateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     ListTemplateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: ListTemplateRequest{AppId: "test", PageNum: 1, PageSize: 1}, wantErr: false},
+		{name: "required", req: ListTemplateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ListTemplateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListTemplateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiListTemplate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiListTemplate, "app_id", "test")
+}
+
+func TestCreateTemplateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     CreateTemplateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: CreateTemplateRequest{AppId: "test", TitleId: "test", KidList: []string{"test"}, SceneDesc: "test"}, wantErr: false},
+		{name: "required", req: CreateTemplateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := CreateTemplateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTemplateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.TplId != "TPL_ID" {
+				t.Errorf("resp.TplId = %v, want %v", resp.TplId, "TPL_ID")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiCreateTemplate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiCreateTemplate, "app_id", "test")
+}
+
+func TestDeleteTemplateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     DeleteTemplateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: DeleteTemplateRequest{AppId: "test", TplId: "test"}, wantErr: false},
+		{name: "required", req: DeleteTemplateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := DeleteTemplateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteTemplateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiDeleteTemplate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiDeleteTemplate, "app_id", "test")
+}
@@ -15,7 +15,6 @@
 package template_message
 
 import (
-	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -30,13 +29,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestSend(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
-	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiSend, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock, _ := test.LookupMockApi(apiSend)
 
 	type args struct {
 		ctx     *microapp.MicroApp
@@ -48,18 +41,18 @@ func TestSend(t *testing.T) {
 		wantResp []byte
 		wantErr  bool
 	}{
-		{name: "case1", args: args{ctx: test.MockMicroApp}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
 			gotResp, err := Send(tt.args.ctx, tt.args.payload)
 			//fmt.Println(string(gotResp), err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("Send() gotResp = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
@@ -0,0 +1,116 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template_message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fastwego/microapp"
+)
+
+// TemplateData 模版中填充着的数据，key 必须是 keyword 为前缀
+type TemplateData struct {
+	Value string `json:"value"`
+}
+
+// Validate 校验必填参数
+func (req TemplateData) Validate() error {
+	if req.Value == "" {
+		return errors.New("value is required")
+	}
+
+	return nil
+}
+
+// SendRequest 发送模版消息 请求参数
+type SendRequest struct {
+	Touser     string                  `json:"touser"`         // 要发送给用户的 open id
+	TemplateId string                  `json:"template_id"`    // 在开发者平台配置消息模版后获得的模版 id
+	Page       string                  `json:"page,omitempty"` // 点击消息卡片之后打开的小程序页面地址
+	FormId     string                  `json:"form_id"`        // 可以通过 <form /> 组件获得 form_id
+	Data       map[string]TemplateData `json:"data"`           // 模版中填充着的数据，key 必须是 keyword 为前缀
+}
+
+// Validate 校验必填参数
+func (req SendRequest) Validate() error {
+	if req.Touser == "" {
+		return errors.New("touser is required")
+	}
+
+	if req.TemplateId == "" {
+		return errors.New("template_id is required")
+	}
+
+	if req.FormId == "" {
+		return errors.New("form_id is required")
+	}
+
+	if len(req.Data) == 0 {
+		return errors.New("data is required")
+	}
+
+	for key, item := range req.Data {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("data[%s]: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// SendResponse 发送模版消息 响应
+type SendResponse struct {
+	Errcode int64  `json:"errcode"`
+	Errmsg  string `json:"errmsg"`
+}
+
+/*
+发送模版消息
+
+使用结构体 作为请求参数和响应 的 Send，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/model-news/send
+
+POST https://developer.toutiao.com/api/apps/game/template/send
+*/
+func SendTyped(ctx *microapp.MicroApp, req SendRequest) (resp SendResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		SendRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := Send(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template_message
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestSendTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     SendRequest
+		wantErr bool
+	}{
+		{name: "case1", req: SendRequest{Touser: "test", TemplateId: "test", FormId: "test", Data: map[string]TemplateData{"test": {Value: "test"}}}, wantErr: false},
+		{name: "required", req: SendRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := SendTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.Errmsg != "ok" {
+				t.Errorf("resp.Errmsg = %v, want %v", resp.Errmsg, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiSend, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiSend, "touser", "test")
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package url_link
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gosync "sync"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	"github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/microapp"
+)
+
+// 缓存的 URL Link 在 过期前 expireMargin 失效，避免 分享出去的链接 已过期
+const expireMargin = 10 * time.Minute
+
+// expireTimeUnit expire_time 向下取整的 精度（秒），使用 now + N 作为 expire_time 的请求 在 同一分钟内 命中缓存
+const expireTimeUnit = 60
+
+var errorGenerateAborted = errors.New("url_link: generate aborted")
+
+/*
+LinkCache 进程内缓存 生成的 URL Link
+
+expire_time 向下取整到 分钟 后 参数相同的请求 直接返回 缓存的链接，不重复生成，
+链接 不会晚于 请求的 expire_time 过期；生成数量 受配额限制，见 QueryQuota；
+相同的请求 并发时 只生成一次，不同请求 互不阻塞；零值 可直接使用
+
+	links := url_link.NewLinkCache()
+	link, err := links.Generate(ctx, url_link.GenerateRequest{AppName: "douyin", Path: "pages/index", ExpireTime: expireTime})
+*/
+type LinkCache struct {
+	Cache cachego.Cache // 默认为 进程内缓存，可替换为 其他 cachego 实现 以便 多个进程共享
+
+	once  gosync.Once
+	mutex gosync.Mutex
+	calls map[string]*linkCall // 进行中的 生成请求，按 缓存 key
+	now   func() time.Time
+}
+
+// linkCall 进行中的 生成请求，完成后 关闭 done
+type linkCall struct {
+	done    chan struct{}
+	urlLink string
+	err     error
+}
+
+/*
+创建 URL Link 缓存
+*/
+func NewLinkCache() *LinkCache {
+	return &LinkCache{
+		Cache: sync.New(),
+		calls: map[string]*linkCall{},
+		now:   time.Now,
+	}
+}
+
+// init 零值 LinkCache 使用 默认值
+func (links *LinkCache) init() {
+	if links.Cache == nil {
+		links.Cache = sync.New()
+	}
+	if links.calls == nil {
+		links.calls = map[string]*linkCall{}
+	}
+	if links.now == nil {
+		links.now = time.Now
+	}
+}
+
+/*
+Generate 生成 URL Link，命中缓存时 直接返回
+
+MaAppId 为空时 使用 ctx.Config.AppId，ExpireTime 向下取整到 分钟；err_no 不为 0 时 返回错误，不缓存
+*/
+func (links *LinkCache) Generate(ctx *microapp.MicroApp, req GenerateRequest) (urlLink string, err error) {
+	links.once.Do(links.init)
+
+	if req.MaAppId == "" {
+		req.MaAppId = ctx.Config.AppId
+	}
+	if err = req.Validate(); err != nil {
+		return
+	}
+	req.ExpireTime -= req.ExpireTime % expireTimeUnit
+
+	key, err := linkKey(req)
+	if err != nil {
+		return
+	}
+	if urlLink, _ = links.Cache.Fetch(key); urlLink != "" {
+		return
+	}
+
+	// 相同的请求 等待 进行中的 生成结果
+	links.mutex.Lock()
+	if call, ok := links.calls[key]; ok {
+		links.mutex.Unlock()
+		<-call.done
+		return call.urlLink, call.err
+	}
+	call := &linkCall{done: make(chan struct{}), err: errorGenerateAborted}
+	links.calls[key] = call
+	links.mutex.Unlock()
+
+	// generate panic 时 等待的请求 也能返回
+	defer func() {
+		links.mutex.Lock()
+		delete(links.calls, key)
+		links.mutex.Unlock()
+		close(call.done)
+	}()
+
+	call.urlLink, call.err = links.generate(ctx, key, req)
+	return call.urlLink, call.err
+}
+
+// generate 调用接口 生成 URL Link 并缓存 到 过期前 expireMargin
+func (links *LinkCache) generate(ctx *microapp.MicroApp, key string, req GenerateRequest) (urlLink string, err error) {
+	// 检查缓存后 其他请求 可能已经生成 并缓存
+	if urlLink, _ = links.Cache.Fetch(key); urlLink != "" {
+		return
+	}
+
+	resp, err := GenerateTyped(ctx, req)
+	if err != nil {
+		return
+	}
+	if resp.ErrNo != 0 {
+		return "", fmt.Errorf("err_no %d: %s", resp.ErrNo, resp.ErrTips)
+	}
+
+	if ttl := time.Unix(req.ExpireTime, 0).Sub(links.now()) - expireMargin; ttl > 0 {
+		_ = links.Cache.Save(key, resp.UrlLink, ttl)
+	}
+	return resp.UrlLink, nil
+}
+
+// linkKey 按 请求参数 生成缓存 key
+func linkKey(req GenerateRequest) (key string, err error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	sum := md5.Sum(data)
+	return "url_link:" + hex.EncodeToString(sum[:]), nil
+}
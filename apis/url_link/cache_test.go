@@ -0,0 +1,260 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package url_link
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestLinkCache(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	var calls int32
+	env.Mux.HandleFunc(apiGenerate, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		req := GenerateRequest{}
+		_ = json.Unmarshal(body, &req)
+		if req.Path == "pages/error" {
+			_, _ = w.Write([]byte(`{"err_no":10003,"err_tips":"quota exceeded"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"err_no":0,"err_tips":"success","url_link":"https://z.douyin.com/%s/%d"}`, req.MaAppId, n)
+	})
+
+	now := time.Unix(1600000000, 0)
+	links := NewLinkCache()
+	links.now = func() time.Time { return now }
+
+	expireTime := now.Add(24 * time.Hour).Unix()
+	index := GenerateRequest{AppName: "douyin", Path: "pages/index", ExpireTime: expireTime}
+
+	first, err := links.Generate(env.MicroApp, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != "https://z.douyin.com/APPID/1" {
+		t.Errorf("link = %s, want ma_app_id filled from config", first)
+	}
+
+	second, err := links.Generate(env.MicroApp, index)
+	if err != nil || second != first || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("identical request: link = %s, calls = %d, want cached %s", second, calls, first)
+	}
+
+	other := index
+	other.Query = `{"id":"1"}`
+	if link, _ := links.Generate(env.MicroApp, other); link == first {
+		t.Errorf("different query should generate a new link, got %s", link)
+	}
+
+	// 即将过期的链接 不缓存
+	soon := GenerateRequest{AppName: "douyin", Path: "pages/soon", ExpireTime: now.Add(time.Minute).Unix()}
+	a, _ := links.Generate(env.MicroApp, soon)
+	b, _ := links.Generate(env.MicroApp, soon)
+	if a == b {
+		t.Errorf("link expiring within margin should not be cached, got %s twice", a)
+	}
+
+	failed := GenerateRequest{AppName: "douyin", Path: "pages/error", ExpireTime: expireTime}
+	for i := 0; i < 2; i++ {
+		if _, err = links.Generate(env.MicroApp, failed); err == nil {
+			t.Fatal("err_no should be returned as error")
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 6 {
+		t.Errorf("calls = %d, want 6 (errors are not cached)", got)
+	}
+
+	if _, err = links.Generate(env.MicroApp, GenerateRequest{Path: "pages/index"}); err == nil {
+		t.Error("missing app_name should fail validation")
+	}
+}
+
+func TestLinkCacheExpireTime(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	var calls int32
+	env.Mux.HandleFunc(apiGenerate, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"err_no":0,"err_tips":"success","url_link":"https://z.douyin.com/%d"}`, atomic.AddInt32(&calls, 1))
+	})
+
+	now := time.Unix(1600000000, 0)
+	links := NewLinkCache()
+	links.now = func() time.Time { return now }
+
+	expireTime := now.Add(24*time.Hour).Unix() / 60 * 60
+	req := GenerateRequest{AppName: "douyin", Path: "pages/index", ExpireTime: expireTime + 30}
+	first, _ := links.Generate(env.MicroApp, req)
+
+	// 向下取整到 分钟，链接 不会晚于 请求的 expire_time 过期
+	env.Capture.AssertJSONField(t, apiGenerate, "expire_time", float64(expireTime))
+
+	// 同一分钟内的 expire_time 命中缓存
+	sameMinute := req
+	sameMinute.ExpireTime = expireTime + 59
+	if link, _ := links.Generate(env.MicroApp, sameMinute); link != first || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("same minute: link = %s, calls = %d, want cached %s", link, calls, first)
+	}
+
+	// 缓存的链接 比请求的 expire_time 晚过期，不能使用
+	shorter := req
+	shorter.ExpireTime = now.Add(time.Hour).Unix()
+	if link, _ := links.Generate(env.MicroApp, shorter); link == first || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("shorter expire_time: link = %s, calls = %d, want a new link", link, calls)
+	}
+
+	// 缓存的链接 早于请求的 expire_time 过期，同样 重新生成
+	longer := req
+	longer.ExpireTime = now.Add(48 * time.Hour).Unix()
+	if link, _ := links.Generate(env.MicroApp, longer); link == first || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("longer expire_time: link = %s, calls = %d, want a new link", link, calls)
+	}
+	if link, _ := links.Generate(env.MicroApp, req); link != first || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("original expire_time: link = %s, calls = %d, want cached %s", link, calls, first)
+	}
+}
+
+func TestLinkCacheZeroValue(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	links := &LinkCache{}
+	req := GenerateRequest{AppName: "douyin", Path: "pages/index", ExpireTime: time.Now().Add(24 * time.Hour).Unix()}
+	first, err := links.Generate(env.MicroApp, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link, err := links.Generate(env.MicroApp, req); err != nil || link != first {
+		t.Errorf("link = %s, err = %v, want cached %s", link, err, first)
+	}
+}
+
+func TestLinkCachePanic(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	var once sync.Once
+	started := make(chan struct{})
+	release := make(chan struct{})
+	env.Mux.HandleFunc(apiGenerate, func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(started) })
+		<-release
+		_, _ = w.Write([]byte(`{"err_no":0,"err_tips":"success","url_link":"https://z.douyin.com/1"}`))
+	})
+
+	// 生成时 panic，等待的请求 返回错误 而不是 一直阻塞
+	links := NewLinkCache()
+	links.now = func() time.Time { panic("clock") }
+	req := GenerateRequest{AppName: "douyin", Path: "pages/index", ExpireTime: time.Now().Add(24 * time.Hour).Unix()}
+
+	generate := func(result chan<- error) {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		_, err := links.Generate(env.MicroApp, req)
+		result <- err
+	}
+
+	first := make(chan error, 1)
+	go generate(first)
+	<-started
+
+	waiter := make(chan error, 1)
+	go generate(waiter)
+	time.Sleep(10 * time.Millisecond) // waiter 等待 进行中的 生成
+	close(release)
+
+	for _, result := range []chan error{first, waiter} {
+		select {
+		case err := <-result:
+			if err == nil {
+				t.Error("err = nil, want error after panic")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("blocked after panic")
+		}
+	}
+}
+
+func TestLinkCacheConcurrent(t *testing.T) {
+	t.Parallel()
+	env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+
+	var calls int32
+	release := make(chan struct{})
+	env.Mux.HandleFunc(apiGenerate, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := GenerateRequest{}
+		_ = json.Unmarshal(body, &req)
+		if req.Path == "pages/slow" {
+			<-release
+		}
+		_, _ = fmt.Fprintf(w, `{"err_no":0,"err_tips":"success","url_link":"https://z.douyin.com/%d"}`, atomic.AddInt32(&calls, 1))
+	})
+
+	links := NewLinkCache()
+	expireTime := time.Now().Add(24*time.Hour).Unix() / 60 * 60
+
+	// 同一分钟内的 expire_time 与 缓存 使用 相同的 key，只生成一次
+	results := make(chan string, 3)
+	for i := 0; i < 3; i++ {
+		slow := GenerateRequest{AppName: "douyin", Path: "pages/slow", ExpireTime: expireTime + int64(i)}
+		go func() {
+			link, _ := links.Generate(env.MicroApp, slow)
+			results <- link
+		}()
+	}
+
+	// 其他 key 不等待 进行中的 慢请求
+	done := make(chan error, 1)
+	go func() {
+		_, err := links.Generate(env.MicroApp, GenerateRequest{AppName: "douyin", Path: "pages/fast", ExpireTime: expireTime})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request for another key blocked by an in-flight request")
+	}
+
+	close(release)
+	first := <-results
+	for i := 0; i < 2; i++ {
+		if link := <-results; link != first {
+			t.Errorf("concurrent identical requests: links %s and %s", first, link)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want 2 (one per key)", got)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package url_link URL Link
+package url_link
+
+import (
+	"bytes"
+
+	"github.com/fastwego/microapp"
+)
+
+const (
+	apiGenerate   = "/api/apps/url_link/generate"
+	apiQueryInfo  = "/api/apps/url_link/query_info"
+	apiQueryQuota = "/api/apps/url_link/query_quota"
+)
+
+/*
+生成 URL Link
+
+生成 能够直接跳转到 小程序 的 URL Link，可在 短信、网页 等场景中 打开小程序
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/generate
+
+POST https://developer.toutiao.com/api/apps/url_link/generate
+*/
+func Generate(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiGenerate, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+查询 URL Link
+
+查询 URL Link 的配置
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-info
+
+POST https://developer.toutiao.com/api/apps/url_link/query_info
+*/
+func QueryInfo(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiQueryInfo, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+
+/*
+查询 URL Link 配额
+
+查询 小程序 URL Link 的 已使用数量 和 上限
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-quota
+
+POST https://developer.toutiao.com/api/apps/url_link/query_quota
+*/
+func QueryQuota(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	return ctx.Client.HTTPPost(apiQueryQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package url_link
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestGenerate(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiGenerate)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := Generate(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("Generate() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestQueryInfo(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiQueryInfo)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := QueryInfo(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("QueryInfo() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+func TestQueryQuota(t *testing.T) {
+	mock, _ := test.LookupMockApi(apiQueryQuota)
+
+	type args struct {
+		ctx     *microapp.MicroApp
+		payload []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantResp []byte
+		wantErr  bool
+	}{
+		{name: "case1", args: args{ctx: test.MockMicroApp, payload: []byte(`{"access_token":"ACCESS_TOKEN"}`)}, wantResp: []byte(mock.Response), wantErr: false},
+		{name: "no access_token", args: args{ctx: test.MockMicroApp}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := QueryQuota(tt.args.ctx, tt.args.payload)
+			//fmt.Println(string(gotResp), err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryQuota() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("QueryQuota() gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package url_link
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fastwego/microapp"
+)
+
+// GenerateRequest 生成 URL Link 请求参数
+type GenerateRequest struct {
+	MaAppId    string `json:"ma_app_id"`       // 小程序 appid
+	AppName    string `json:"app_name"`        // 打开 URL Link 的宿主 app 名称 douyin douyinlite
+	Path       string `json:"path,omitempty"`  // 打开的页面路径，为空时 打开首页
+	Query      string `json:"query,omitempty"` // 页面参数，JSON 字符串，如 {"id":"1"}
+	ExpireTime int64  `json:"expire_time"`     // 过期时间，unix 时间戳，最长为 180 天后
+}
+
+// Validate 校验必填参数
+func (req GenerateRequest) Validate() error {
+	if req.MaAppId == "" {
+		return errors.New("ma_app_id is required")
+	}
+
+	if req.AppName == "" {
+		return errors.New("app_name is required")
+	}
+
+	if req.ExpireTime == 0 {
+		return errors.New("expire_time is required")
+	}
+
+	return nil
+}
+
+// GenerateResponse 生成 URL Link 响应
+type GenerateResponse struct {
+	ErrNo   int64  `json:"err_no"`   // 错误码
+	ErrTips string `json:"err_tips"` // 错误信息
+	UrlLink string `json:"url_link"` // 生成的 URL Link
+}
+
+// QueryInfoRequest 查询 URL Link 请求参数
+type QueryInfoRequest struct {
+	MaAppId string `json:"ma_app_id"` // 小程序 appid
+	UrlLink string `json:"url_link"`  // URL Link
+}
+
+// Validate 校验必填参数
+func (req QueryInfoRequest) Validate() error {
+	if req.MaAppId == "" {
+		return errors.New("ma_app_id is required")
+	}
+
+	if req.UrlLink == "" {
+		return errors.New("url_link is required")
+	}
+
+	return nil
+}
+
+// UrlLinkInfo URL Link 的配置
+type UrlLinkInfo struct {
+	MaAppId    string `json:"ma_app_id"`   // 小程序 appid
+	AppName    string `json:"app_name"`    // 宿主 app 名称
+	Path       string `json:"path"`        // 打开的页面路径
+	Query      string `json:"query"`       // 页面参数
+	CreateTime int64  `json:"create_time"` // 创建时间，unix 时间戳
+	ExpireTime int64  `json:"expire_time"` // 过期时间，unix 时间戳
+}
+
+// QueryInfoResponse 查询 URL Link 响应
+type QueryInfoResponse struct {
+	ErrNo       int64        `json:"err_no"`        // 错误码
+	ErrTips     string       `json:"err_tips"`      // 错误信息
+	UrlLinkInfo *UrlLinkInfo `json:"url_link_info"` // URL Link 的配置
+}
+
+// QueryQuotaRequest 查询 URL Link 配额 请求参数
+type QueryQuotaRequest struct {
+	MaAppId string `json:"ma_app_id"` // 小程序 appid
+}
+
+// Validate 校验必填参数
+func (req QueryQuotaRequest) Validate() error {
+	if req.MaAppId == "" {
+		return errors.New("ma_app_id is required")
+	}
+
+	return nil
+}
+
+// UrlLinkQuota URL Link 配额
+type UrlLinkQuota struct {
+	UrlLinkUsed  int64 `json:"url_link_used"`  // 已生成的数量
+	UrlLinkLimit int64 `json:"url_link_limit"` // 生成数量上限
+}
+
+// QueryQuotaResponse 查询 URL Link 配额 响应
+type QueryQuotaResponse struct {
+	ErrNo        int64         `json:"err_no"`         // 错误码
+	ErrTips      string        `json:"err_tips"`       // 错误信息
+	UrlLinkQuota *UrlLinkQuota `json:"url_link_quota"` // URL Link 配额
+}
+
+/*
+生成 URL Link
+
+使用结构体 作为请求参数和响应 的 Generate，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/generate
+
+POST https://developer.toutiao.com/api/apps/url_link/generate
+*/
+func GenerateTyped(ctx *microapp.MicroApp, req GenerateRequest) (resp GenerateResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		GenerateRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := Generate(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询 URL Link
+
+使用结构体 作为请求参数和响应 的 QueryInfo，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-info
+
+POST https://developer.toutiao.com/api/apps/url_link/query_info
+*/
+func QueryInfoTyped(ctx *microapp.MicroApp, req QueryInfoRequest) (resp QueryInfoResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		QueryInfoRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := QueryInfo(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
+
+/*
+查询 URL Link 配额
+
+使用结构体 作为请求参数和响应 的 QueryQuota，调用前校验必填参数，自动填充 access_token
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-quota
+
+POST https://developer.toutiao.com/api/apps/url_link/query_quota
+*/
+func QueryQuotaTyped(ctx *microapp.MicroApp, req QueryQuotaRequest) (resp QueryQuotaResponse, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	var accessToken string
+	accessToken, err = ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+		QueryQuotaRequest
+	}{accessToken, req})
+	if err != nil {
+		return
+	}
+
+	raw, err := QueryQuota(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(raw, &resp)
+	return
+}
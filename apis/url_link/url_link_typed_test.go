@@ -0,0 +1,103 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package url_link
+
+import (
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/test"
+)
+
+func TestGenerateTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     GenerateRequest
+		wantErr bool
+	}{
+		{name: "case1", req: GenerateRequest{MaAppId: "test", AppName: "test", ExpireTime: 1}, wantErr: false},
+		{name: "required", req: GenerateRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := GenerateTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.UrlLink != "URL_LINK" {
+				t.Errorf("resp.UrlLink = %v, want %v", resp.UrlLink, "URL_LINK")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiGenerate, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiGenerate, "ma_app_id", "test")
+}
+
+func TestQueryInfoTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     QueryInfoRequest
+		wantErr bool
+	}{
+		{name: "case1", req: QueryInfoRequest{MaAppId: "test", UrlLink: "test"}, wantErr: false},
+		{name: "required", req: QueryInfoRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := QueryInfoTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryInfoTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiQueryInfo, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiQueryInfo, "ma_app_id", "test")
+}
+
+func TestQueryQuotaTyped(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     QueryQuotaRequest
+		wantErr bool
+	}{
+		{name: "case1", req: QueryQuotaRequest{MaAppId: "test"}, wantErr: false},
+		{name: "required", req: QueryQuotaRequest{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := QueryQuotaTyped(env.MicroApp, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryQuotaTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp.ErrTips != "ok" {
+				t.Errorf("resp.ErrTips = %v, want %v", resp.ErrTips, "ok")
+			}
+		})
+	}
+
+	env.Capture.AssertAccessToken(t, apiQueryQuota, env.MicroApp)
+	env.Capture.AssertJSONField(t, apiQueryQuota, "ma_app_id", "test")
+}
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
@@ -34,17 +35,41 @@ var (
 	ErrorSystemBusy        = errors.New("system busy")
 )
 
+/*
+StatusError api 服务器 响应的 http 状态码 不为 200（401 除外，见 ErrorAccessTokenExpire）
+*/
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "Status " + e.Status
+}
+
 /*
 HttpClient 用于向接口发送请求
 */
 type Client struct {
-	Ctx *MicroApp
+	Ctx        *MicroApp
+	HTTPClient *http.Client // 为空时使用 http.DefaultClient，可设置超时等
+	ServerUrl  string       // 为空时使用 全局 ServerUrl，可为每个实例指定不同的 api 服务器
+}
+
+// NewRequest 创建 发往 api 服务器 的请求
+func (client *Client) NewRequest(method string, uri string, body io.Reader) (req *http.Request, err error) {
+	serverUrl := client.ServerUrl
+	if serverUrl == "" {
+		serverUrl = ServerUrl
+	}
+
+	return http.NewRequest(method, serverUrl+uri, body)
 }
 
 // HTTPGet GET 请求
 func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
 
-	req, err := http.NewRequest(http.MethodGet, ServerUrl+uri, nil)
+	req, err := client.NewRequest(http.MethodGet, uri, nil)
 	if err != nil {
 		return
 	}
@@ -55,7 +80,7 @@ func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
 //HTTPPost POST 请求
 func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string) (resp []byte, err error) {
 
-	req, err := http.NewRequest(http.MethodPost, ServerUrl+uri, payload)
+	req, err := client.NewRequest(http.MethodPost, uri, payload)
 	if err != nil {
 		return
 	}
@@ -65,11 +90,26 @@ func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string
 	return client.HTTPDo(req)
 }
 
-//HTTPDo 执行 请求
+func (client *Client) httpClient() *http.Client {
+	if client.HTTPClient != nil {
+		return client.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+/*
+HTTPDo 执行 请求
+
+access_token 过期 或 系统繁忙 时 重试一次；
+请求体 不可重放（req.GetBody 为空，如 io.Pipe 流式上传）时 不缓存 请求体，也不重试，access_token 过期时 仅通知过期
+*/
 func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 
+	// 流式请求体 边读边发，无法 再次发送
+	replayable := req.Body == nil || req.GetBody != nil
+
 	var body, body2 []byte
-	if req.Body != nil {
+	if req.Body != nil && replayable {
 
 		body, err = ioutil.ReadAll(req.Body)
 		if err != nil {
@@ -87,7 +127,7 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 		client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, req.URL.String(), req.Header)
 	}
 
-	response, err := http.DefaultClient.Do(req)
+	response, err := client.httpClient().Do(req)
 	if err != nil {
 		return
 	}
@@ -95,6 +135,15 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 
 	resp, err = responseFilter(response)
 
+	if !replayable {
+		if err == ErrorAccessTokenExpire {
+			if noticeErr := client.Ctx.NoticeAccessTokenExpireHandler(client.Ctx); noticeErr != nil {
+				return resp, noticeErr
+			}
+		}
+		return
+	}
+
 	// 发现 access_token 过期
 	if err == ErrorAccessTokenExpire {
 
@@ -116,6 +165,9 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 		if q.Get("access_token") != "" {
 			q.Set("access_token", accessToken)
 			req.URL.RawQuery = q.Encode()
+		} else if q.Get("authorizer_access_token") != "" { // 第三方平台 代授权小程序 调用接口
+			q.Set("authorizer_access_token", accessToken)
+			req.URL.RawQuery = q.Encode()
 		} else if req.Header.Get("X-Token") != "" {
 			req.Header.Set("X-Token", accessToken)
 		} else {
@@ -141,11 +193,13 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 
 		req.Body = ioutil.NopCloser(bytes.NewReader(body2))
 		req.ContentLength = int64(len(body2))
-		response, err = http.DefaultClient.Do(req)
+		response, err = client.httpClient().Do(req)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
+		defer response.Body.Close()
+
 		resp, err = responseFilter(response)
 	} else if err == ErrorSystemBusy {
 
@@ -155,10 +209,11 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 
 		req.Body = ioutil.NopCloser(bytes.NewReader(body2))
 		req.ContentLength = int64(len(body2))
-		response, err = http.DefaultClient.Do(req)
+		response, err = client.httpClient().Do(req)
 		if err != nil {
 			return
 		}
+		defer response.Body.Close()
 
 		resp, err = responseFilter(response)
 	}
@@ -169,9 +224,11 @@ func (client *Client) HTTPDo(req *http.Request) (resp []byte, err error) {
 /*
 筛查 api 服务器响应，判断以下错误：
 
-- http 状态码 不为 200
+- http 状态码 不为 200，返回 *StatusError
 
 - 接口响应错误码 errcode 不为 0
+
+图片等二进制响应 不做错误码检查
 */
 func responseFilter(response *http.Response) (resp []byte, err error) {
 	if response.StatusCode != http.StatusOK {
@@ -181,7 +238,7 @@ func responseFilter(response *http.Response) (resp []byte, err error) {
 			return
 		}
 
-		err = fmt.Errorf("Status %s", response.Status)
+		err = &StatusError{StatusCode: response.StatusCode, Status: response.Status}
 		return
 	}
 
@@ -190,6 +247,11 @@ func responseFilter(response *http.Response) (resp []byte, err error) {
 		return
 	}
 
+	// 图片等二进制响应（如 二维码）没有错误码
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "image/") {
+		return
+	}
+
 	errorResponse := struct {
 		Errcode int64  `json:"errcode"`
 		Errmsg  string `json:"errmsg"`
@@ -240,7 +302,7 @@ func GetAccessToken(ctx *MicroApp) (accessToken string, err error) {
 		return
 	}
 
-	accessToken, expiresIn, err := refreshAccessToken(ctx.Config.AppId, ctx.Config.AppSecret)
+	accessToken, expiresIn, err := refreshAccessToken(ctx)
 	if err != nil {
 		return
 	}
@@ -275,14 +337,19 @@ func NoticeAccessTokenExpire(ctx *MicroApp) (err error) {
 
 See: https://developers.weixin.qq.com/doc/offiaccount/Basic_Information/Get_access_token.html
 */
-func refreshAccessToken(appid string, secret string) (accessToken string, expiresIn int, err error) {
+func refreshAccessToken(ctx *MicroApp) (accessToken string, expiresIn int, err error) {
 	params := url.Values{}
-	params.Add("appid", appid)
-	params.Add("secret", secret)
+	params.Add("appid", ctx.Config.AppId)
+	params.Add("secret", ctx.Config.AppSecret)
 	params.Add("grant_type", "client_credential")
-	url := ServerUrl + "/api/apps/token?" + params.Encode()
 
-	response, err := http.Get(url)
+	req, err := ctx.Client.NewRequest(http.MethodGet, "/api/apps/token?"+params.Encode(), nil)
+	if err != nil {
+		return
+	}
+	url := req.URL.String()
+
+	response, err := ctx.Client.httpClient().Do(req)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,85 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package microapp
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseFilter(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n"
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantResp    string
+		wantErr     bool
+		err         error
+	}{
+		{name: "ok", status: http.StatusOK, contentType: "application/json", body: `{"errcode":0,"errmsg":"ok"}`, wantResp: `{"errcode":0,"errmsg":"ok"}`},
+		{name: "errcode", status: http.StatusOK, contentType: "application/json", body: `{"errcode":40001,"errmsg":"bad params"}`, wantErr: true},
+		{name: "expired", status: http.StatusOK, contentType: "application/json", body: `{"errcode":40002}`, err: ErrorAccessTokenExpire},
+		{name: "errno", status: http.StatusOK, contentType: "application/json", body: `{"errno":40002,"message":"other meaning"}`, wantResp: `{"errno":40002,"message":"other meaning"}`},
+		{name: "busy", status: http.StatusOK, contentType: "application/json", body: `{"errcode":-1}`, err: ErrorSystemBusy},
+		{name: "unauthorized", status: http.StatusUnauthorized, err: ErrorAccessTokenExpire},
+		{name: "image", status: http.StatusOK, contentType: "image/png", body: png, wantResp: png},
+		{name: "binary without image content type", status: http.StatusOK, contentType: "application/octet-stream", body: png, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				StatusCode: tt.status,
+				Status:     http.StatusText(tt.status),
+				Header:     http.Header{"Content-Type": []string{tt.contentType}},
+				Body:       ioutil.NopCloser(strings.NewReader(tt.body)),
+			}
+			resp, err := responseFilter(response)
+			switch {
+			case tt.err != nil:
+				if err != tt.err {
+					t.Errorf("err = %v, want %v", err, tt.err)
+				}
+			case tt.wantErr:
+				if err == nil {
+					t.Errorf("resp = %s, want error", resp)
+				}
+			default:
+				if err != nil || string(resp) != tt.wantResp {
+					t.Errorf("responseFilter() = %q, %v", resp, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseFilterStatus(t *testing.T) {
+	response := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Status:     "502 Bad Gateway",
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}
+	_, err := responseFilter(response)
+
+	var statusError *StatusError
+	if !errors.As(err, &statusError) || statusError.StatusCode != http.StatusBadGateway || err.Error() != "Status 502 Bad Gateway" {
+		t.Errorf("err = %v, want *StatusError", err)
+	}
+}
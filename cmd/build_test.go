@@ -0,0 +1,242 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// firstDiff 第一处不同的行
+func firstDiff(got, want []byte) (line int, gotLine string, wantLine string) {
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		gotLine, wantLine = "", ""
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if gotLine != wantLine {
+			return i + 1, gotLine, wantLine
+		}
+	}
+	return 0, "", ""
+}
+
+// assertGolden 比较 生成的文件 与 dir 目录中的文件
+func assertGolden(t *testing.T, dir string, files map[string][]byte) {
+	tmp := t.TempDir()
+	if err := writeFiles(tmp, files); err != nil {
+		t.Fatal(err)
+	}
+
+	for filename := range files {
+		got, err := ioutil.ReadFile(filepath.Join(tmp, filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadFile(filepath.Join(dir, filename))
+		if err != nil {
+			t.Errorf("%s: %v, run `go run .` in cmd to regenerate", filename, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			line, gotLine, wantLine := firstDiff(got, want)
+			t.Errorf("%s:%d differs from generated code, run `go run .` in cmd to regenerate\n generated: %s\n checked-in: %s", filename, line, gotLine, wantLine)
+		}
+	}
+}
+
+// 重新生成 apis 下的每个包、test 包中的模拟接口 和 命令列表，与仓库中的代码比较
+func TestBuildGolden(t *testing.T) {
+	apiConfig, err := loadSpec("spec/schema.json", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range apiConfig {
+		group := group
+		t.Run(group.Package, func(t *testing.T) {
+			files, err := build(group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, filepath.Join("..", "apis"), files)
+		})
+	}
+
+	t.Run("mocks", func(t *testing.T) {
+		files, err := buildMocks(apiConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, filepath.Join("..", "test"), files)
+	})
+
+	t.Run("cli", func(t *testing.T) {
+		files, err := buildCLI(apiConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "microapp", files)
+	})
+}
+
+func TestSampleJSON(t *testing.T) {
+	fields := []Field{
+		{Name: "errcode", Type: "int64"},
+		{Name: "errmsg", Type: "string"},
+		{Name: "openid", Type: "string"},
+		{Name: "tags", Type: "[]string"},
+		{Name: "data", Type: "[]object", Fields: []Field{
+			{Name: "hit", Type: "bool"},
+			{Name: "prob", Type: "float64"},
+		}},
+	}
+	want := `{"errcode":0,"errmsg":"ok","openid":"OPENID","tags":["TAGS"],"data":[{"hit":false,"prob":0}]}`
+	if got := sampleJSON(fields); got != want {
+		t.Errorf("sampleJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestFormatSource(t *testing.T) {
+	src := `package demo
+
+func Demo(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	json := 1
+	_ = json
+	return ctx.Client.HTTPPost(apiDemo, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+`
+	got, err := formatSource(src, map[string]string{"demo": "github.com/fastwego/microapp/apis/demo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := licenseHeader + `package demo
+
+import (
+	"bytes"
+
+	"github.com/fastwego/microapp"
+)
+
+func Demo(ctx *microapp.MicroApp, payload []byte) (resp []byte, err error) {
+	json := 1
+	_ = json
+	return ctx.Client.HTTPPost(apiDemo, bytes.NewReader(payload), "application/json;charset=utf-8")
+}
+`
+	if string(got) != want {
+		t.Errorf("formatSource() = %s, want %s", got, want)
+	}
+
+	if _, err = formatSource("package demo\nfunc {", nil); err == nil {
+		t.Error("formatSource() accepted invalid code")
+	}
+}
+
+func TestSelectGroups(t *testing.T) {
+	groups := []ApiGroup{{Package: "auth"}, {Package: "qrcode"}, {Package: "data_caching"}}
+
+	selected, err := selectGroups(groups, "")
+	if err != nil || len(selected) != 3 {
+		t.Fatalf("selectGroups(all) = %v, %v", selected, err)
+	}
+
+	selected, err = selectGroups(groups, "qrcode, auth")
+	if err != nil || len(selected) != 2 || selected[0].Package != "qrcode" || selected[1].Package != "auth" {
+		t.Fatalf("selectGroups(qrcode, auth) = %v, %v", selected, err)
+	}
+
+	if _, err = selectGroups(groups, "auth,unknown"); err == nil {
+		t.Error("selectGroups() accepted unknown package")
+	}
+}
+
+func TestBuildHandwritten(t *testing.T) {
+	group := ApiGroup{
+		Name:    `演示`,
+		Package: `demo`,
+		Apis: []Api{
+			{
+				Name:        "生成",
+				Description: "生成的接口",
+				Request:     "POST https://developer.toutiao.com/api/apps/demo/generated",
+				See:         "https://example.com",
+				FuncName:    "Generated",
+			},
+			{
+				Name:        "手写",
+				Description: "手写的接口",
+				Request:     "POST https://developer.toutiao.com/api/apps/demo/handwritten",
+				See:         "https://example.com",
+				FuncName:    "Handwritten",
+				Handwritten: true,
+			},
+		},
+	}
+
+	files, err := build(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for filename, content := range files {
+		if strings.Contains(string(content), "Handwritten") {
+			t.Errorf("%s contains handwritten api", filename)
+		}
+	}
+	if !strings.Contains(string(files["demo/demo.go"]), "func Generated(") {
+		t.Error("demo/demo.go missing generated api")
+	}
+
+	group.Apis = group.Apis[1:]
+	if files, err = build(group); err != nil || len(files) != 0 {
+		t.Errorf("build(all handwritten) = %v, %v", files, err)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"demo/demo.go":      []byte("package demo\n"),
+		"demo/demo_test.go": []byte("package demo\n"),
+	}
+	if err := writeFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := checkFiles(dir, files)
+	if err != nil || len(stale) != 0 {
+		t.Fatalf("checkFiles(unchanged) = %v, %v", stale, err)
+	}
+
+	files["demo/demo.go"] = []byte("package demo\n\nconst changed = 1\n")
+	files["demo/example_demo_test.go"] = []byte("package demo\n")
+	stale, err = checkFiles(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "demo", "demo.go"), filepath.Join(dir, "demo", "example_demo_test.go")}
+	if strings.Join(stale, ",") != strings.Join(want, ",") {
+		t.Errorf("checkFiles() = %v, want %v", stale, want)
+	}
+}
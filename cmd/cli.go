@@ -0,0 +1,88 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// cliFile 生成的命令列表 相对 microapp 命令目录的文件名
+const cliFile = "commands.go"
+
+/*
+buildCLI 生成 microapp 命令行工具 的命令列表
+
+每个接口 对应一个子命令 microapp <包名> <函数名的 kebab 形式>，包含 全部分组的接口（手写实现的接口 也可以调用）
+*/
+func buildCLI(groups []ApiGroup) (files map[string][]byte, err error) {
+	var items []string
+	for _, group := range groups {
+		for _, api := range group.Apis {
+			method, link := splitRequest(api.Request)
+			u, err := url.Parse(link)
+			if err != nil {
+				return nil, err
+			}
+
+			item := fmt.Sprintf("{\n\t\tGroup: %s,\n\t\tName: %s,\n\t\tTitle: %s,\n\t\tSee: %s,\n\t\tMethod: %s,\n\t\tHost: %s,\n\t\tPath: %s,\n",
+				strconv.Quote(group.Package), strconv.Quote(commandName(api)), strconv.Quote(api.Name), strconv.Quote(api.See), strconv.Quote(method), strconv.Quote(u.Scheme+"://"+u.Host), strconv.Quote(u.Path))
+			if api.Auth != "" {
+				item += fmt.Sprintf("\t\tAuth: %s,\n", strconv.Quote(api.Auth))
+			}
+			if api.Sign == "payment_salt" {
+				item += fmt.Sprintf("\t\tSign: %s,\n", strconv.Quote(api.Sign))
+			}
+			if len(api.GetParams) > 0 {
+				var params []string
+				for _, param := range api.GetParams {
+					params = append(params, fmt.Sprintf("{Name: %s, Required: %t, Description: %s}",
+						strconv.Quote(param.Name), param.Required, strconv.Quote(cleanText(param.Description))))
+				}
+				item += fmt.Sprintf("\t\tParams: []param{\n\t\t\t%s,\n\t\t},\n", strings.Join(params, ",\n\t\t\t"))
+			}
+			if strings.HasPrefix(api.Request, "POST(@") {
+				upload, fields := parseUpload(api.Request)
+				item += fmt.Sprintf("\t\tUpload: %s,\n", strconv.Quote(upload))
+				if len(fields) > 0 {
+					var quoted []string
+					for _, field := range fields {
+						quoted = append(quoted, strconv.Quote(field))
+					}
+					item += fmt.Sprintf("\t\tFields: []string{%s},\n", strings.Join(quoted, ", "))
+				}
+			} else if method == "POST" {
+				item += "\t\tBody: true,\n"
+			}
+			items = append(items, item+"\t}")
+		}
+	}
+
+	code := "package main\n\n// commands 根据 cmd/spec 中的接口定义 生成\nvar commands = []command{\n\t" + strings.Join(items, ",\n\t") + ",\n}\n"
+	formatted, err := formatSource(code, nil)
+	if err != nil {
+		return
+	}
+	return map[string][]byte{cliFile: formatted}, nil
+}
+
+// commandName 子命令名称，如 CreateQRCode => create-qr-code
+func commandName(api Api) string {
+	return strcase.ToKebab(funcName(api))
+}
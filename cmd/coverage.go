@@ -0,0 +1,256 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ServerUrl 文档站点
+const ServerUrl = `https://microapp.bytedance.com`
+
+// 文档索引中 服务端 API 页面的路径前缀
+const serverDocPrefix = "/docs/zh-CN/mini-app/develop/server/"
+
+// 标题中包含 以下关键字的页面 视为已废弃
+var deprecatedKeywords = []string{"废弃", "下线", "停止维护", "deprecated"}
+
+// 标题中包含 以下关键字的页面 不是接口，不统计
+var overviewKeywords = []string{"概述", "介绍", "简介", "说明"}
+
+// docLink 文档索引中的 接口页面
+type docLink struct {
+	Title   string `json:"title"`
+	Section string `json:"section"`
+	URL     string `json:"url"`
+}
+
+type coverageApi struct {
+	Package  string `json:"package"`
+	Name     string `json:"name"`
+	FuncName string `json:"func_name"`
+	Request  string `json:"request"`
+	See      string `json:"see"`
+}
+
+type coverageDeprecated struct {
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Reason  string `json:"reason"`
+}
+
+// coverageReport 接口覆盖率报告
+type coverageReport struct {
+	Summary struct {
+		Total       int     `json:"total"`
+		Implemented int     `json:"implemented"`
+		Missing     int     `json:"missing"`
+		Deprecated  int     `json:"deprecated"`
+		Percent     float64 `json:"percent"`
+	} `json:"summary"`
+	Implemented []coverageApi        `json:"implemented"`
+	Missing     []docLink            `json:"missing"`
+	Deprecated  []coverageDeprecated `json:"deprecated"`
+}
+
+// coverage 读取 文档索引，输出 覆盖率报告
+func coverage(groups []ApiGroup, linksFile string, format string, out string) (err error) {
+	html, err := ioutil.ReadFile(linksFile)
+	if err != nil {
+		return
+	}
+	links, err := parseDocLinks(html)
+	if err != nil {
+		return
+	}
+	report := buildCoverage(groups, links)
+
+	var output []byte
+	switch format {
+	case "markdown", "md":
+		output = report.Markdown()
+	case "json":
+		output, err = report.JSON()
+	default:
+		err = fmt.Errorf("unknown format %s", format)
+	}
+	if err != nil {
+		return
+	}
+
+	if out == "-" {
+		_, err = os.Stdout.Write(output)
+		return
+	}
+	return ioutil.WriteFile(out, output, 0644)
+}
+
+// parseDocLinks 解析 保存的文档索引页面，按路径去重
+func parseDocLinks(html []byte) (links []docLink, err error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		docPath := normalizeDocPath(href)
+		if !strings.HasPrefix(docPath, serverDocPrefix) || seen[docPath] {
+			return
+		}
+		seen[docPath] = true
+
+		section := strings.SplitN(strings.TrimPrefix(docPath, serverDocPrefix), "/", 2)[0]
+		links = append(links, docLink{
+			Title:   cleanText(a.Text()),
+			Section: section,
+			URL:     ServerUrl + docPath,
+		})
+	})
+	return
+}
+
+// normalizeDocPath 去掉 域名、query、锚点 和 结尾的 /
+func normalizeDocPath(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(path.Clean("/"+u.Path), "/")
+}
+
+func containsAny(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+buildCoverage 比较 文档索引 和 接口定义
+
+	implemented 文档索引中 且 已实现的接口（按 See 链接匹配）
+	missing     文档索引中 未实现的接口
+	deprecated  文档标题标记为废弃的接口，以及 已实现 但不在文档索引中的接口
+*/
+func buildCoverage(groups []ApiGroup, links []docLink) (report coverageReport) {
+	report.Implemented = []coverageApi{}
+	report.Missing = []docLink{}
+	report.Deprecated = []coverageDeprecated{}
+
+	implemented := map[string]coverageApi{}
+	for _, group := range groups {
+		for _, api := range group.Apis {
+			// 同一文档的多个接口（如 文件上传），只统计第一个
+			if _, ok := implemented[normalizeDocPath(api.See)]; ok || api.See == "" {
+				continue
+			}
+			implemented[normalizeDocPath(api.See)] = coverageApi{
+				Package:  group.Package,
+				Name:     api.Name,
+				FuncName: funcName(api),
+				Request:  api.Request,
+				See:      api.See,
+			}
+		}
+	}
+
+	indexed := map[string]bool{}
+	for _, link := range links {
+		docPath := normalizeDocPath(link.URL)
+		indexed[docPath] = true
+		api, ok := implemented[docPath]
+
+		switch {
+		case containsAny(link.Title, deprecatedKeywords):
+			item := coverageDeprecated{Name: link.Title, URL: link.URL, Reason: "文档已标记废弃"}
+			if ok {
+				item.Package, item.Name = api.Package, api.Name
+			}
+			report.Deprecated = append(report.Deprecated, item)
+		case ok:
+			report.Implemented = append(report.Implemented, api)
+		case containsAny(link.Title, overviewKeywords):
+		default:
+			report.Missing = append(report.Missing, link)
+		}
+	}
+
+	for docPath, api := range implemented {
+		if !indexed[docPath] {
+			report.Deprecated = append(report.Deprecated, coverageDeprecated{Package: api.Package, Name: api.Name, URL: api.See, Reason: "文档索引中不存在"})
+		}
+	}
+	sort.SliceStable(report.Deprecated, func(i, j int) bool {
+		return report.Deprecated[i].URL < report.Deprecated[j].URL
+	})
+
+	report.Summary.Implemented = len(report.Implemented)
+	report.Summary.Missing = len(report.Missing)
+	report.Summary.Deprecated = len(report.Deprecated)
+	report.Summary.Total = report.Summary.Implemented + report.Summary.Missing
+	if report.Summary.Total > 0 {
+		report.Summary.Percent = float64(report.Summary.Implemented*1000/report.Summary.Total) / 10
+	}
+	return
+}
+
+// Markdown 输出 markdown 格式的报告
+func (report coverageReport) Markdown() []byte {
+	var b bytes.Buffer
+	b.WriteString("# 接口覆盖率\n\n")
+	fmt.Fprintf(&b, "已实现 %d / %d（%.1f%%），未实现 %d，已废弃 %d\n", report.Summary.Implemented, report.Summary.Total, report.Summary.Percent, report.Summary.Missing, report.Summary.Deprecated)
+
+	b.WriteString("\n## 已实现\n\n| 包 | 接口 | 方法 | 请求 |\n| --- | --- | --- | --- |\n")
+	for _, api := range report.Implemented {
+		fmt.Fprintf(&b, "| %s | [%s](%s) | %s | `%s` |\n", api.Package, api.Name, api.See, api.FuncName, api.Request)
+	}
+
+	b.WriteString("\n## 未实现\n\n| 分类 | 接口 |\n| --- | --- |\n")
+	for _, link := range report.Missing {
+		fmt.Fprintf(&b, "| %s | [%s](%s) |\n", link.Section, link.Title, link.URL)
+	}
+
+	b.WriteString("\n## 已废弃\n\n| 包 | 接口 | 原因 |\n| --- | --- | --- |\n")
+	for _, item := range report.Deprecated {
+		fmt.Fprintf(&b, "| %s | [%s](%s) | %s |\n", item.Package, item.Name, item.URL, item.Reason)
+	}
+	return b.Bytes()
+}
+
+// JSON 输出 JSON 格式的报告
+func (report coverageReport) JSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(report)
+	return buf.Bytes(), err
+}
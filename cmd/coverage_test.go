@@ -0,0 +1,83 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	html, err := ioutil.ReadFile(filepath.Join("testdata", "doc_links.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	links, err := parseDocLinks(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 11 {
+		t.Fatalf("parseDocLinks() = %d links, want 11 server pages without duplicates", len(links))
+	}
+
+	// 接口定义 使用固定的 testdata/coverage，与 文档索引 testdata/doc_links.html 对应，新增接口 不影响统计
+	groups, err := loadSpec("spec/schema.json", filepath.Join("testdata", "coverage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := buildCoverage(groups, links)
+	if report.Summary.Implemented != 7 || report.Summary.Missing != 2 || report.Summary.Deprecated != 2 || report.Summary.Total != 9 || report.Summary.Percent != 77.7 {
+		t.Errorf("summary = %+v", report.Summary)
+	}
+
+	var missing []string
+	for _, link := range report.Missing {
+		missing = append(missing, link.Section+"/"+link.Title)
+	}
+	if got := strings.Join(missing, ","); got != "interface-request-credential/getAccessToken,url-link/生成 URL Link" {
+		t.Errorf("missing = %s", got)
+	}
+
+	if item := report.Deprecated[0]; item.Package != "legacy" || item.Reason != "文档索引中不存在" {
+		t.Errorf("deprecated[0] = %+v", item)
+	}
+	if item := report.Deprecated[1]; item.Package != "template_message" || item.Name != "发送模版消息" || item.Reason != "文档已标记废弃" {
+		t.Errorf("deprecated[1] = %+v", item)
+	}
+
+	markdown := string(report.Markdown())
+	for _, want := range []string{
+		"已实现 7 / 9（77.7%），未实现 2，已废弃 2",
+		"| auth | [code2Session](https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/log-in/code-2-session) | Code2Session | `GET https://developer.toutiao.com/api/apps/jscode2session` |",
+		"| url-link | [生成 URL Link](https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/generate) |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %s\n%s", want, markdown)
+		}
+	}
+
+	output, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded coverageReport
+	if err = json.Unmarshal(output, &decoded); err != nil || decoded.Summary != report.Summary || len(decoded.Missing) != 2 {
+		t.Errorf("JSON() = %s, %v", output, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// 生成代码中 可能用到的包
+var knownImports = map[string]string{
+	"bytes":     "bytes",
+	"context":   "context",
+	"errors":    "errors",
+	"fmt":       "fmt",
+	"io":        "io",
+	"ioutil":    "io/ioutil",
+	"json":      "encoding/json",
+	"multipart": "mime/multipart",
+	"http":      "net/http",
+	"url":       "net/url",
+	"os":        "os",
+	"path":      "path",
+	"reflect":   "reflect",
+	"strconv":   "strconv",
+	"strings":   "strings",
+	"testing":   "testing",
+	"time":      "time",
+	"microapp":  "github.com/fastwego/microapp",
+	"test":      "github.com/fastwego/microapp/test",
+}
+
+const licenseHeader = `// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+`
+
+/*
+formatSource 补全 import 并格式化 生成的代码
+
+src 不包含 import 声明，根据代码中引用的包名 从 knownImports 和 extraImports 中查找导入路径
+*/
+func formatSource(src string, extraImports map[string]string) (formatted []byte, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse generated code: %v\n%s", err, src)
+	}
+
+	used := map[string]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok || ident.Obj != nil {
+			return true
+		}
+		if importPath, ok := extraImports[ident.Name]; ok {
+			used[ident.Name] = importPath
+		} else if importPath, ok := knownImports[ident.Name]; ok {
+			used[ident.Name] = importPath
+		}
+		return true
+	})
+
+	var std, thirdParty []string
+	for _, importPath := range used {
+		if strings.Contains(importPath, ".") {
+			thirdParty = append(thirdParty, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(thirdParty)
+
+	var groups []string
+	for _, paths := range [][]string{std, thirdParty} {
+		if len(paths) == 0 {
+			continue
+		}
+		lines := make([]string, len(paths))
+		for i, importPath := range paths {
+			lines[i] = "\t\"" + importPath + "\""
+		}
+		groups = append(groups, strings.Join(lines, "\n"))
+	}
+
+	offset := fset.Position(file.Name.End()).Offset
+	code := src[:offset]
+	if len(groups) > 0 {
+		code += "\n\nimport (\n" + strings.Join(groups, "\n\n") + "\n)"
+	}
+	code += src[offset:]
+
+	formatted, err = format.Source([]byte(licenseHeader + code))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v\n%s", err, code)
+	}
+	return
+}
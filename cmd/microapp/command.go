@@ -0,0 +1,309 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/ecpay"
+)
+
+// param query 参数，每个参数 对应一个同名的 flag
+type param struct {
+	Name        string
+	Required    bool
+	Description string
+}
+
+// command 子命令 microapp <Group> <Name>，对应一个接口
+type command struct {
+	Group  string // 包名
+	Name   string // 函数名的 kebab 形式
+	Title  string
+	See    string
+	Method string
+	Host   string // 接口的服务器地址，未指定 -server 时 使用
+	Path   string
+	Auth   string // query: access_token 参数 body: access_token 字段 header: X-Token 请求头 secret: appid/secret 参数
+	Sign   string // payment_salt: 使用 支付密钥 SALT 填充 请求体中的 app_id 和 sign
+	Params []param
+	Body   bool     // JSON 请求体
+	Upload string   // 上传接口 文件的表单字段
+	Fields []string // 上传接口 其他表单字段
+}
+
+// options 命令参数
+type options struct {
+	params url.Values
+	body   string // JSON 请求体文件，- 为标准输入
+	file   string // 上传的文件
+	fields map[string]string
+	output string // 输出文件，- 为标准输出
+}
+
+// parse 解析 命令参数，检查 必填参数
+func (cmd command) parse(args []string, stderr io.Writer) (opts options, err error) {
+	fs := flag.NewFlagSet("microapp "+cmd.Group+" "+cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	params := map[string]*string{}
+	for _, p := range cmd.Params {
+		usage := p.Description
+		if p.Required {
+			usage += "（必填）"
+		}
+		params[p.Name] = fs.String(p.Name, "", usage)
+	}
+	if cmd.Body {
+		fs.StringVar(&opts.body, "body", "", "JSON 请求体文件，- 为标准输入，默认 {}")
+	}
+	fields := map[string]*string{}
+	if cmd.Upload != "" {
+		fs.StringVar(&opts.file, "file", "", "上传的文件（必填）")
+		for _, field := range cmd.Fields {
+			fields[field] = fs.String(field, "", "表单字段 "+field)
+		}
+	}
+	fs.StringVar(&opts.output, "o", "", "输出文件，- 为标准输出；二进制响应 必须指定")
+
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "%s\n\n\t%s %s%s\n\tSee: %s\n\nusage: microapp %s %s [flags]\n", cmd.Title, cmd.Method, cmd.Host, cmd.Path, cmd.See, cmd.Group, cmd.Name)
+		fs.PrintDefaults()
+	}
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %s", fs.Arg(0))
+	}
+
+	opts.params = url.Values{}
+	for _, p := range cmd.Params {
+		value := *params[p.Name]
+		if value == "" {
+			if p.Required {
+				return opts, fmt.Errorf("missing required flag -%s", p.Name)
+			}
+			continue
+		}
+		opts.params.Set(p.Name, value)
+	}
+
+	if cmd.Upload != "" && opts.file == "" {
+		return opts, errors.New("missing required flag -file")
+	}
+	opts.fields = map[string]string{}
+	for name, value := range fields {
+		if *value != "" {
+			opts.fields[name] = *value
+		}
+	}
+	return
+}
+
+// execute 发送请求 并输出响应
+func (cmd command) execute(ctx *microapp.MicroApp, opts options, stdin io.Reader, stdout io.Writer) (err error) {
+	req, err := cmd.newRequest(ctx, opts, stdin)
+	if err != nil {
+		return
+	}
+
+	resp, err := ctx.Client.HTTPDo(req)
+	if err != nil {
+		return
+	}
+
+	return writeResponse(resp, opts.output, stdout)
+}
+
+// newRequest 按鉴权方式 放入 access_token 或 appid/secret，构造请求
+func (cmd command) newRequest(ctx *microapp.MicroApp, opts options, stdin io.Reader) (req *http.Request, err error) {
+	params := url.Values{}
+	for name, values := range opts.params {
+		params[name] = values
+	}
+
+	var accessToken string
+	switch cmd.Auth {
+	case "query", "body", "header":
+		accessToken, err = ctx.GetAccessTokenHandler(ctx)
+		if err != nil {
+			return
+		}
+	case "secret":
+		params.Set("appid", ctx.Config.AppId)
+		params.Set("secret", ctx.Config.AppSecret)
+	}
+	if cmd.Auth == "query" {
+		params.Set("access_token", accessToken)
+	}
+
+	var body io.Reader
+	var contentType string
+	switch {
+	case cmd.Upload != "":
+		body, contentType, err = cmd.multipartBody(opts)
+	case cmd.Body:
+		var payload []byte
+		payload, err = readBody(opts.body, stdin)
+		if err == nil && cmd.Auth == "body" {
+			payload, err = setAccessToken(payload, accessToken)
+		}
+		if err == nil && cmd.Sign == "payment_salt" {
+			payload, err = signPayment(payload, ctx.Config)
+		}
+		body, contentType = bytes.NewReader(payload), "application/json;charset=utf-8"
+	}
+	if err != nil {
+		return
+	}
+
+	uri := cmd.Path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+	// 接口分布在 多个域名（如 第三方平台接口），未指定 server 时 使用 接口自身的服务器地址
+	if ctx.Client.ServerUrl == "" && cmd.Host != "" {
+		req, err = http.NewRequest(cmd.Method, cmd.Host+uri, body)
+	} else {
+		req, err = ctx.Client.NewRequest(cmd.Method, uri, body)
+	}
+	if err != nil {
+		return
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if cmd.Auth == "header" {
+		req.Header.Set("X-Token", accessToken)
+	}
+	return
+}
+
+// readBody 读取 JSON 请求体，filename 为 - 时 读取标准输入，为空时 使用 {}
+func readBody(filename string, stdin io.Reader) (body []byte, err error) {
+	switch filename {
+	case "":
+		return []byte("{}"), nil
+	case "-":
+		body, err = ioutil.ReadAll(stdin)
+	default:
+		body, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("request body is not valid JSON")
+	}
+	return
+}
+
+// setAccessToken 在 JSON 请求体 中设置 access_token 字段
+func setAccessToken(body []byte, accessToken string) (payload []byte, err error) {
+	return updateBody(body, func(data map[string]interface{}) {
+		data["access_token"] = accessToken
+	})
+}
+
+// signPayment 在 JSON 请求体 中设置 app_id，并使用 支付密钥 SALT 计算 sign（担保支付 接口）
+func signPayment(body []byte, config microapp.Config) (payload []byte, err error) {
+	if config.PaymentSalt == "" {
+		return nil, ecpay.ErrorNoPaymentSalt
+	}
+	return updateBody(body, func(data map[string]interface{}) {
+		data["app_id"] = config.AppId
+		data["sign"] = ecpay.Sign(config.PaymentSalt, data)
+	})
+}
+
+// updateBody 修改 JSON 请求体 中的字段，数字 保持原样
+func updateBody(body []byte, update func(data map[string]interface{})) (payload []byte, err error) {
+	data := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err = decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("request body must be a JSON object: %v", err)
+	}
+	update(data)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(data)
+	return buf.Bytes(), err
+}
+
+// multipartBody 上传的文件 和 表单字段
+func (cmd command) multipartBody(opts options) (body io.Reader, contentType string, err error) {
+	file, err := os.Open(opts.file)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	m := multipart.NewWriter(&buf)
+	part, err := m.CreateFormFile(cmd.Upload, filepath.Base(opts.file))
+	if err != nil {
+		return
+	}
+	if _, err = io.Copy(part, file); err != nil {
+		return
+	}
+	for _, field := range cmd.Fields {
+		if value, ok := opts.fields[field]; ok {
+			if err = m.WriteField(field, value); err != nil {
+				return
+			}
+		}
+	}
+	if err = m.Close(); err != nil {
+		return
+	}
+	return &buf, m.FormDataContentType(), nil
+}
+
+// writeResponse JSON 响应 格式化后输出，二进制响应 必须指定输出文件
+func writeResponse(resp []byte, output string, stdout io.Writer) (err error) {
+	if json.Valid(resp) {
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, resp, "", "  "); err != nil {
+			return
+		}
+		buf.WriteByte('\n')
+		resp = buf.Bytes()
+	} else if output == "" {
+		return fmt.Errorf("binary response (%d bytes), use -o to write it to a file", len(resp))
+	}
+
+	if output == "" || output == "-" {
+		_, err = stdout.Write(resp)
+		return
+	}
+	return ioutil.WriteFile(output, resp, 0644)
+}
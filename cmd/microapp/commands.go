@@ -0,0 +1,399 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// commands 根据 cmd/spec 中的接口定义 生成
+var commands = []command{
+	{
+		Group:  "auth",
+		Name:   "code-2-session",
+		Title:  "code2Session",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/log-in/code-2-session",
+		Method: "GET",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/jscode2session",
+		Auth:   "secret",
+		Params: []param{
+			{Name: "code", Required: false, Description: "login 接口返回的登录凭证"},
+			{Name: "anonymous_code", Required: false, Description: "login 接口返回的匿名登录凭证"},
+		},
+	},
+	{
+		Group:  "code_management",
+		Name:   "upload",
+		Title:  "上传代码",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/upload-code",
+		Method: "POST",
+		Host:   "https://open.microapp.bytedance.com",
+		Path:   "/openapi/v1/microapp/package/upload",
+		Params: []param{
+			{Name: "component_appid", Required: true, Description: "第三方平台 appid"},
+			{Name: "authorizer_access_token", Required: true, Description: "授权小程序 接口调用凭据"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "code_management",
+		Name:   "audit",
+		Title:  "提审代码",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/audit-code",
+		Method: "POST",
+		Host:   "https://open.microapp.bytedance.com",
+		Path:   "/openapi/v1/microapp/package/audit",
+		Params: []param{
+			{Name: "component_appid", Required: true, Description: "第三方平台 appid"},
+			{Name: "authorizer_access_token", Required: true, Description: "授权小程序 接口调用凭据"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "code_management",
+		Name:   "versions",
+		Title:  "查询版本",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/get-version-list",
+		Method: "GET",
+		Host:   "https://open.microapp.bytedance.com",
+		Path:   "/openapi/v1/microapp/package/versions",
+		Params: []param{
+			{Name: "component_appid", Required: true, Description: "第三方平台 appid"},
+			{Name: "authorizer_access_token", Required: true, Description: "授权小程序 接口调用凭据"},
+		},
+	},
+	{
+		Group:  "code_management",
+		Name:   "release",
+		Title:  "发布代码",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/release-code",
+		Method: "POST",
+		Host:   "https://open.microapp.bytedance.com",
+		Path:   "/openapi/v1/microapp/package/release",
+		Params: []param{
+			{Name: "component_appid", Required: true, Description: "第三方平台 appid"},
+			{Name: "authorizer_access_token", Required: true, Description: "授权小程序 接口调用凭据"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "code_management",
+		Name:   "rollback",
+		Title:  "回退代码版本",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/code/rollback-code",
+		Method: "POST",
+		Host:   "https://open.microapp.bytedance.com",
+		Path:   "/openapi/v1/microapp/package/rollback",
+		Params: []param{
+			{Name: "component_appid", Required: true, Description: "第三方平台 appid"},
+			{Name: "authorizer_access_token", Required: true, Description: "授权小程序 接口调用凭据"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "content_security",
+		Name:   "text-anti-dirty",
+		Title:  "内容安全检测",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/content-security-detect",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/v2/tags/text/antidirt",
+		Auth:   "header",
+		Body:   true,
+	},
+	{
+		Group:  "content_security",
+		Name:   "image",
+		Title:  "图片检测",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/picture-detect",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/v2/tags/image/",
+		Auth:   "header",
+		Body:   true,
+	},
+	{
+		Group:  "content_security",
+		Name:   "image-file",
+		Title:  "图片检测（文件上传）",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/content-security/picture-detect",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/v2/tags/image/",
+		Auth:   "header",
+		Upload: "image",
+		Fields: []string{"targets"},
+	},
+	{
+		Group:  "data_caching",
+		Name:   "set-user-storage",
+		Title:  "setUserStorage",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/data-caching/set-user-storage",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/set_user_storage",
+		Auth:   "query",
+		Params: []param{
+			{Name: "openid", Required: true, Description: "登录用户唯一标识"},
+			{Name: "signature", Required: true, Description: "用户登录态签名"},
+			{Name: "sig_method", Required: true, Description: "用户登录态签名的编码方法，目前只支持 hmac_sha256"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "data_caching",
+		Name:   "remove-user-storage",
+		Title:  "removeUserStorage",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/data-caching/remove-user-storage",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/remove_user_storage",
+		Auth:   "query",
+		Params: []param{
+			{Name: "openid", Required: true, Description: "登录用户唯一标识"},
+			{Name: "signature", Required: true, Description: "用户登录态签名"},
+			{Name: "sig_method", Required: true, Description: "用户登录态签名的编码方法，目前只支持 hmac_sha256"},
+		},
+		Body: true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "create-order",
+		Title:  "预下单",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/pay-list/pay",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/create_order",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "query-order",
+		Title:  "查询订单",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/pay-list/query",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/query_order",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "create-refund",
+		Title:  "退款",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/refund-list/refund",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/create_refund",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "query-refund",
+		Title:  "查询退款",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/refund-list/query",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/query_refund",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "settle",
+		Title:  "结算及分账",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/settle-list/settle",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/settle",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "ecpay",
+		Name:   "query-settle",
+		Title:  "查询结算",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/ecpay/APIlist/settle-list/query",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/ecpay/v1/query_settle",
+		Sign:   "payment_salt",
+		Body:   true,
+	},
+	{
+		Group:  "order",
+		Name:   "push",
+		Title:  "订单同步",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/order/order-sync",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/order/v2/push",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "qrcode",
+		Name:   "create-qr-code",
+		Title:  "createQRCode",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/qr-code/create-qr-code",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/qrcode",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "share",
+		Name:   "list-template",
+		Title:  "查询分享模板列表",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-list",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/share/template/list",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "share",
+		Name:   "create-template",
+		Title:  "创建分享模板",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/template-create",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/share/template/create",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "share",
+		Name:   "query-share-data",
+		Title:  "查询分享数据",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/share/share-data",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/share/data",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "notify",
+		Title:  "订阅消息推送",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/notify",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/notify",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "list-library",
+		Title:  "查询模板库",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/template/library/list",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "list-library-keywords",
+		Title:  "查询模板库关键词",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-library-keywords",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/template/library/keywords",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "list-template",
+		Title:  "查询小程序模板",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/list-template",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/template/list",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "create-template",
+		Title:  "创建小程序模板",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/create-template",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/template/create",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "subscribe_notification",
+		Name:   "delete-template",
+		Title:  "删除小程序模板",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/subscribe-notification/delete-template",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/subscribe_notification/developer/v1/template/delete",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "template_message",
+		Name:   "send",
+		Title:  "发送模版消息",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/model-news/send",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/game/template/send",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "url_link",
+		Name:   "generate",
+		Title:  "生成 URL Link",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/generate",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/url_link/generate",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "url_link",
+		Name:   "query-info",
+		Title:  "查询 URL Link",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-info",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/url_link/query_info",
+		Auth:   "body",
+		Body:   true,
+	},
+	{
+		Group:  "url_link",
+		Name:   "query-quota",
+		Title:  "查询 URL Link 配额",
+		See:    "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/url-link/query-quota",
+		Method: "POST",
+		Host:   "https://developer.toutiao.com",
+		Path:   "/api/apps/url_link/query_quota",
+		Auth:   "body",
+		Body:   true,
+	},
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+microapp 命令行工具，运维和调试时 直接调用接口，无需手动拼装 access_token
+
+	microapp                                                 # 列出全部分组
+	microapp qrcode                                          # 列出分组中的命令
+	microapp qrcode create-qr-code -h                        # 命令的参数
+	microapp qrcode create-qr-code -body qrcode.json -o qrcode.png
+	echo '{"path":"pages/index"}' | microapp qrcode create-qr-code -body - -o qrcode.png
+	microapp auth code-2-session -code CODE
+	microapp content_security image-file -file image.png -targets '["porn"]'
+
+appid/secret 读取自 环境变量 MICROAPP_APPID MICROAPP_SECRET，或 配置文件（-config，默认 ~/.microapp.json），环境变量优先：
+
+	{"appid": "APPID", "secret": "SECRET"}
+
+担保支付（ecpay）命令 使用 支付密钥 SALT 自动填充 请求体中的 app_id 和 sign，SALT 读取自 环境变量 MICROAPP_PAYMENT_SALT 或 配置文件 payment_salt
+
+请求 发往 各接口自身的服务器地址（如 第三方平台接口 为 https://open.microapp.bytedance.com）；
+配置 server（或 环境变量 MICROAPP_SERVER、-server 参数）后 全部请求 发往该地址，用于 调试 或 模拟服务器
+
+access_token 按接口的鉴权方式 自动获取，放入 query 参数、请求体 或 X-Token 请求头，缓存在 临时目录 中 供多次调用共用
+
+响应为 JSON 时 格式化后输出到 标准输出 或 -o 指定的文件；二进制响应（如 二维码图片）必须使用 -o 指定输出文件，- 为标准输出
+
+命令列表 commands.go 由 cmd 根据接口定义 生成，新增接口后 在 cmd 目录执行 go run . 即可
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fastwego/microapp"
+)
+
+// config 命令行工具配置
+type config struct {
+	AppId       string `json:"appid"`
+	Secret      string `json:"secret"`
+	PaymentSalt string `json:"payment_salt"` // 担保支付 支付密钥 SALT，担保支付 命令 用于签名
+	Server      string `json:"server"`       // api 服务器地址，为空时 使用 各接口自身的服务器地址
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+/*
+run 执行命令，返回 退出码
+
+0 成功 1 请求失败 2 参数错误
+*/
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("microapp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configFile := fs.String("config", "", "配置文件，默认 ~/.microapp.json")
+	server := fs.String("server", "", "api 服务器地址，覆盖配置文件中的 server；默认 使用 各接口自身的服务器地址")
+	verbose := fs.Bool("v", false, "输出 请求日志 到 标准错误")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: microapp [-config file] [-server url] [-v] <group> <command> [flags]")
+		fs.PrintDefaults()
+		fmt.Fprintln(stderr, "\ngroups:")
+		for _, group := range groupNames() {
+			fmt.Fprintln(stderr, "\t"+group)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	switch fs.NArg() {
+	case 0:
+		fs.Usage()
+		return 2
+	case 1:
+		return listCommands(fs.Arg(0), stderr)
+	}
+
+	cmd, ok := lookupCommand(fs.Arg(0), fs.Arg(1))
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %s %s\n", fs.Arg(0), fs.Arg(1))
+		return 2
+	}
+
+	opts, err := cmd.parse(fs.Args()[2:], stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	conf, err := loadConfig(*configFile, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *server != "" {
+		conf.Server = *server
+	}
+	if cmd.Auth != "" && (conf.AppId == "" || conf.Secret == "") {
+		fmt.Fprintln(stderr, "appid/secret not configured: set MICROAPP_APPID and MICROAPP_SECRET, or use -config")
+		return 2
+	}
+
+	if cmd.Sign == "payment_salt" && (conf.AppId == "" || conf.PaymentSalt == "") {
+		fmt.Fprintln(stderr, "appid/payment_salt not configured: set MICROAPP_APPID and MICROAPP_PAYMENT_SALT, or use -config")
+		return 2
+	}
+
+	app := microapp.New(microapp.Config{AppId: conf.AppId, AppSecret: conf.Secret, PaymentSalt: conf.PaymentSalt})
+	app.Client.ServerUrl = conf.Server
+	app.Logger = nil
+	if *verbose {
+		app.Logger = log.New(stderr, "[fastwego/microapp] ", log.LstdFlags)
+	}
+
+	if err = cmd.execute(app, opts, stdin, stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+/*
+loadConfig 读取 配置文件，再使用 环境变量 MICROAPP_APPID MICROAPP_SECRET MICROAPP_SERVER MICROAPP_PAYMENT_SALT 覆盖
+
+filename 为空时 使用 ~/.microapp.json，该文件不存在时 忽略
+*/
+func loadConfig(filename string, getenv func(string) string) (conf config, err error) {
+	optional := filename == ""
+	if optional {
+		home, _ := os.UserHomeDir()
+		filename = filepath.Join(home, ".microapp.json")
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err) && optional:
+		err = nil
+	case err != nil:
+		return
+	default:
+		if err = json.Unmarshal(data, &conf); err != nil {
+			return conf, fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	for key, value := range map[string]*string{
+		"MICROAPP_APPID":        &conf.AppId,
+		"MICROAPP_SECRET":       &conf.Secret,
+		"MICROAPP_SERVER":       &conf.Server,
+		"MICROAPP_PAYMENT_SALT": &conf.PaymentSalt,
+	} {
+		if env := getenv(key); env != "" {
+			*value = env
+		}
+	}
+	return
+}
+
+// groupNames 全部分组，按名称排序
+func groupNames() (groups []string) {
+	seen := map[string]bool{}
+	for _, cmd := range commands {
+		if !seen[cmd.Group] {
+			seen[cmd.Group] = true
+			groups = append(groups, cmd.Group)
+		}
+	}
+	sort.Strings(groups)
+	return
+}
+
+// listCommands 输出 分组中的命令
+func listCommands(group string, w io.Writer) int {
+	found := false
+	for _, cmd := range commands {
+		if cmd.Group == group {
+			if !found {
+				fmt.Fprintf(w, "usage: microapp %s <command> [flags]\n\ncommands:\n", group)
+				found = true
+			}
+			fmt.Fprintf(w, "\t%-24s %s\n", cmd.Name, cmd.Title)
+		}
+	}
+	if !found {
+		fmt.Fprintf(w, "unknown group %s\n", group)
+	}
+	return 2
+}
+
+func lookupCommand(group string, name string) (cmd command, ok bool) {
+	for _, cmd = range commands {
+		if cmd.Group == group && cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/microapp"
+	"github.com/fastwego/microapp/apis/ecpay"
+	"github.com/fastwego/microapp/test"
+)
+
+// runCLI 使用 env 的模拟服务器 执行命令
+func runCLI(t *testing.T, env *test.Env, stdin string, args ...string) (code int, stdout string, stderr string) {
+	configFile := filepath.Join(t.TempDir(), "microapp.json")
+	conf := `{"appid":"` + env.MicroApp.Config.AppId + `","secret":"` + env.MicroApp.Config.AppSecret + `","payment_salt":"` + env.MicroApp.Config.PaymentSalt + `"}`
+	if err := ioutil.WriteFile(configFile, []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	args = append([]string{"-config", configFile, "-server", env.Server.URL}, args...)
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRunQueryAuth(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{AppId: "CLI_QUERY", AppSecret: "SECRET"})
+
+	code, stdout, stderr := runCLI(t, env, `{"kv_list":[{"key":"k","value":"v"}]}`,
+		"data_caching", "set-user-storage", "-openid", "OPENID", "-signature", "SIGNATURE", "-sig_method", "hmac_sha256", "-body", "-")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "\n  \"errcode\": 0") {
+		t.Errorf("stdout is not pretty JSON: %s", stdout)
+	}
+
+	path := "/api/apps/set_user_storage"
+	env.Capture.AssertAccessToken(t, path, env.MicroApp)
+	env.Capture.AssertQuery(t, path, "openid", "OPENID")
+	if body := string(env.Capture.MustLastRequest(t, path).Body); !strings.Contains(body, `"kv_list"`) {
+		t.Errorf("body = %s, want stdin payload", body)
+	}
+}
+
+func TestRunBodyAuthBinary(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{AppId: "CLI_BODY", AppSecret: "SECRET"})
+	png := []byte("\x89PNG\r\n\x1a\nQRCODE")
+	env.Mux.HandleFunc("/api/apps/qrcode", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(png)
+	})
+
+	body := filepath.Join(t.TempDir(), "qrcode.json")
+	if err := ioutil.WriteFile(body, []byte(`{"appname":"douyin","width":430}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr := runCLI(t, env, "", "qrcode", "create-qr-code", "-body", body)
+	if code != 1 || !strings.Contains(stderr, "use -o") {
+		t.Errorf("binary response without -o: code = %d, stderr: %s", code, stderr)
+	}
+
+	output := filepath.Join(t.TempDir(), "qrcode.png")
+	code, _, stderr = runCLI(t, env, "", "qrcode", "create-qr-code", "-body", body, "-o", output)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	if got, _ := ioutil.ReadFile(output); !bytes.Equal(got, png) {
+		t.Errorf("output = %q, want %q", got, png)
+	}
+
+	path := "/api/apps/qrcode"
+	env.Capture.AssertAccessToken(t, path, env.MicroApp)
+	env.Capture.AssertJSONField(t, path, "appname", "douyin")
+	env.Capture.AssertJSONField(t, path, "width", float64(430))
+}
+
+func TestRunHeaderAuthUpload(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{AppId: "CLI_UPLOAD", AppSecret: "SECRET"})
+
+	image := filepath.Join(t.TempDir(), "image.png")
+	if err := ioutil.WriteFile(image, []byte("IMAGE"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr := runCLI(t, env, "", "content_security", "image-file", "-file", image, "-targets", `["porn"]`)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+
+	path := "/api/v2/tags/image/"
+	env.Capture.AssertHeader(t, path, "X-Token", "ACCESS_TOKEN")
+	body := string(env.Capture.MustLastRequest(t, path).Body)
+	for _, want := range []string{`name="image"; filename="image.png"`, "IMAGE", `name="targets"`, `["porn"]`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("multipart body missing %s", want)
+		}
+	}
+}
+
+func TestRunPaymentSign(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{AppId: "CLI_PAYMENT", AppSecret: "SECRET", PaymentSalt: "SALT"})
+
+	code, _, stderr := runCLI(t, env, `{"out_order_no":"ORDER","sign":"STALE"}`, "ecpay", "query-order", "-body", "-")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+
+	path := "/api/apps/ecpay/v1/query_order"
+	env.Capture.AssertJSONField(t, path, "app_id", "CLI_PAYMENT")
+	env.Capture.AssertJSONField(t, path, "out_order_no", "ORDER")
+	env.Capture.AssertJSONField(t, path, "sign", ecpay.Sign("SALT", map[string]interface{}{"out_order_no": "ORDER"}))
+
+	env = test.NewEnv(t, microapp.Config{AppId: "CLI_PAYMENT", AppSecret: "SECRET"})
+	code, _, stderr = runCLI(t, env, "{}", "ecpay", "query-order", "-body", "-")
+	if code != 2 || !strings.Contains(stderr, "MICROAPP_PAYMENT_SALT") {
+		t.Errorf("missing payment salt: code = %d, stderr: %s", code, stderr)
+	}
+}
+
+func TestCommandHost(t *testing.T) {
+	cmd, ok := lookupCommand("code_management", "versions")
+	if !ok || cmd.Host != "https://open.microapp.bytedance.com" {
+		t.Fatalf("lookupCommand() = %+v, %v", cmd, ok)
+	}
+	opts := options{params: url.Values{"component_appid": {"COMPONENT_APPID"}}}
+
+	app := microapp.New(microapp.Config{})
+	req, err := cmd.newRequest(app, opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path; got != cmd.Host+cmd.Path {
+		t.Errorf("url = %s, want command host", got)
+	}
+
+	// -server 覆盖
+	app.Client.ServerUrl = "http://127.0.0.1:8080"
+	if req, err = cmd.newRequest(app, opts, nil); err != nil || req.URL.Host != "127.0.0.1:8080" {
+		t.Errorf("url = %v, %v, want -server", req.URL, err)
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{AppId: "CLI_USAGE", AppSecret: "SECRET"})
+
+	tests := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{name: "no group", args: nil, stderr: "data_caching"},
+		{name: "list commands", args: []string{"qrcode"}, stderr: "create-qr-code"},
+		{name: "unknown group", args: []string{"unknown"}, stderr: "unknown group"},
+		{name: "unknown command", args: []string{"qrcode", "unknown"}, stderr: "unknown command"},
+		{name: "missing param", args: []string{"data_caching", "set-user-storage", "-openid", "OPENID"}, stderr: "missing required flag -signature"},
+		{name: "missing file", args: []string{"content_security", "image-file"}, stderr: "missing required flag -file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, env, "", tt.args...)
+			if code != 2 || !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("code = %d, stderr = %s, want 2 and %q", code, stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "microapp.json")
+	if err := ioutil.WriteFile(configFile, []byte(`{"appid":"FILE_APPID","secret":"FILE_SECRET"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"MICROAPP_SECRET": "ENV_SECRET"}
+	conf, err := loadConfig(configFile, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.AppId != "FILE_APPID" || conf.Secret != "ENV_SECRET" {
+		t.Errorf("config = %+v, want appid from file and secret from env", conf)
+	}
+
+	if _, err = loadConfig(filepath.Join(t.TempDir(), "missing.json"), func(string) string { return "" }); err == nil {
+		t.Error("explicit missing config file should fail")
+	}
+}
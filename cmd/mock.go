@@ -0,0 +1,135 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// mockFile 生成的模拟接口 相对 test 目录的文件名
+const mockFile = "mock_apis.go"
+
+/*
+buildMocks 生成 test 包中的 模拟接口
+
+包含 全部分组的接口（手写实现的接口 也需要模拟），路径使用字面量，test 包 不依赖 apis 下的包
+*/
+func buildMocks(groups []ApiGroup) (files map[string][]byte, err error) {
+	var items []string
+	for _, group := range groups {
+		for _, api := range group.Apis {
+			method, link := splitRequest(api.Request)
+			u, err := url.Parse(link)
+			if err != nil {
+				return nil, err
+			}
+
+			item := fmt.Sprintf("{\n\t\tPackage: %s,\n\t\tName: %s,\n\t\tMethod: %s,\n\t\tPath: %s,\n",
+				strconv.Quote(group.Package), strconv.Quote(api.Name), strconv.Quote(method), strconv.Quote(u.Path))
+			if contentType := mockContentType(api, method); contentType != "" {
+				item += fmt.Sprintf("\t\tContentType: %s,\n", strconv.Quote(contentType))
+			}
+			if api.Auth != "" {
+				item += fmt.Sprintf("\t\tAuth: %s,\n", strconv.Quote(api.Auth))
+			}
+			if params := requiredParams(api); len(params) > 0 {
+				item += fmt.Sprintf("\t\tRequiredParams: []string{%s},\n", strings.Join(params, ", "))
+			}
+			item += fmt.Sprintf("\t\tResponse: `%s`,\n\t}", mockResponse(api))
+			items = append(items, item)
+		}
+	}
+
+	code := "package test\n\n// mockApis 根据 cmd/spec 中的接口定义 生成\nvar mockApis = []MockApi{\n\t" + strings.Join(items, ",\n\t") + ",\n}\n"
+	formatted, err := formatSource(code, nil)
+	if err != nil {
+		return
+	}
+	return map[string][]byte{mockFile: formatted}, nil
+}
+
+// mockContentType 请求体的 媒体类型，用于区分 同一路径的 JSON 和 文件上传 两种请求方式
+func mockContentType(api Api, method string) string {
+	if strings.Contains(api.Request, "POST(@") {
+		return "multipart/form-data"
+	}
+	if method == "POST" {
+		return "application/json"
+	}
+	return ""
+}
+
+// requiredParams 必填的 query 参数，包括 鉴权参数
+func requiredParams(api Api) (params []string) {
+	switch api.Auth {
+	case "query":
+		params = append(params, strconv.Quote("access_token"))
+	case "secret":
+		params = append(params, strconv.Quote("appid"), strconv.Quote("secret"))
+	}
+	for _, param := range api.GetParams {
+		if param.Required {
+			params = append(params, strconv.Quote(param.Name))
+		}
+	}
+	return
+}
+
+// mockResponse 符合响应结构的 示例响应，未定义响应结构时 返回 errcode 0
+func mockResponse(api Api) string {
+	if len(api.ResponseFields) == 0 {
+		return `{"errcode":0,"errmsg":"ok"}`
+	}
+	return sampleJSON(api.ResponseFields)
+}
+
+/*
+sampleJSON 按字段定义 生成示例 JSON
+
+字符串 为 大写的字段名（如 openid => "OPENID"），错误信息 为 ok，数值 为 0，布尔 为 false
+*/
+func sampleJSON(fields []Field) string {
+	var members []string
+	for _, field := range fields {
+		members = append(members, strconv.Quote(field.Name)+":"+sampleJSONValue(field))
+	}
+	return "{" + strings.Join(members, ",") + "}"
+}
+
+func sampleJSONValue(field Field) string {
+	switch field.Type {
+	case "int", "int64", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	case "[]string":
+		return "[" + strconv.Quote(strings.ToUpper(field.Name)) + "]"
+	case "map[string]string":
+		return `{"key":"VALUE"}`
+	case "object":
+		return sampleJSON(field.Fields)
+	case "[]object":
+		return "[" + sampleJSON(field.Fields) + "]"
+	case "map[string]object":
+		return `{"key":` + sampleJSON(field.Fields) + "}"
+	}
+	if field.Name == "errmsg" || field.Name == "err_tips" {
+		return `"ok"`
+	}
+	return strconv.Quote(strings.ToUpper(field.Name))
+}
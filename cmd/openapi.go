@@ -0,0 +1,393 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+/*
+openapiMain 根据接口定义 输出 OpenAPI 3 文档
+
+	go run . openapi -out openapi.json
+*/
+func openapiMain(args []string) int {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	specFlag := flags.String("spec", "spec", "接口定义 目录或文件，多个以逗号分隔")
+	schemaFile := flags.String("schema", "spec/schema.json", "接口定义的 JSON Schema")
+	out := flags.String("out", "-", "输出文件，- 为标准输出")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	groups, err := loadSpec(*schemaFile, strings.Split(*specFlag, ",")...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		return 1
+	}
+
+	output, err := marshalOpenAPI(buildOpenAPI(groups))
+	if err == nil {
+		if *out == "-" {
+			_, err = os.Stdout.Write(output)
+		} else {
+			err = ioutil.WriteFile(*out, output, 0644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		return 1
+	}
+	return 0
+}
+
+// OpenAPI 3 文档，只包含 用到的部分
+
+type openapiDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openapiInfo                             `json:"info"`
+	Tags       []openapiTag                            `json:"tags"`
+	Paths      map[string]map[string]*openapiOperation `json:"paths"`
+	Components openapiComponents                       `json:"components"`
+}
+
+type openapiInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type openapiServer struct {
+	URL string `json:"url"`
+}
+
+type openapiTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type openapiOperation struct {
+	OperationID  string                     `json:"operationId"`
+	Summary      string                     `json:"summary"`
+	Description  string                     `json:"description,omitempty"`
+	Tags         []string                   `json:"tags"`
+	Servers      []openapiServer            `json:"servers"`
+	ExternalDocs *openapiExternalDocs       `json:"externalDocs,omitempty"`
+	Parameters   []openapiParameter         `json:"parameters,omitempty"`
+	RequestBody  *openapiRequestBody        `json:"requestBody,omitempty"`
+	Responses    map[string]openapiResponse `json:"responses"`
+	Security     []map[string][]string      `json:"security,omitempty"`
+}
+
+type openapiExternalDocs struct {
+	URL string `json:"url"`
+}
+
+type openapiParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openapiSchema `json:"schema"`
+}
+
+type openapiRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openapiMediaType `json:"content"`
+}
+
+type openapiResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openapiMediaType `json:"content,omitempty"`
+}
+
+type openapiMediaType struct {
+	Schema *openapiSchema `json:"schema"`
+}
+
+type openapiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*openapiSchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *openapiSchema            `json:"items,omitempty"`
+	AdditionalProperties *openapiSchema            `json:"additionalProperties,omitempty"`
+}
+
+type openapiComponents struct {
+	Schemas         map[string]*openapiSchema        `json:"schemas"`
+	SecuritySchemes map[string]openapiSecurityScheme `json:"securitySchemes"`
+}
+
+type openapiSecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+const (
+	errcodeEnvelope = "ErrcodeEnvelope"
+	errNoEnvelope   = "ErrNoEnvelope"
+)
+
+/*
+buildOpenAPI 根据接口定义 生成 OpenAPI 3 文档
+
+鉴权方式：
+
+	query  access_token 参数，securitySchemes.accessToken
+	header X-Token 请求头，securitySchemes.xToken
+	secret appid/secret 参数，securitySchemes.appid + securitySchemes.secret
+	body   请求体中的 access_token 字段（OpenAPI 不支持 请求体中的凭证，作为 必填字段）
+
+错误响应：默认为 errcode/errmsg，响应中包含 err_no 的接口 为 err_no/err_tips
+
+接口分布在 多个域名（如 第三方平台接口），servers 按 operation 声明
+*/
+func buildOpenAPI(groups []ApiGroup) *openapiDoc {
+	doc := &openapiDoc{
+		OpenAPI: "3.0.3",
+		Info: openapiInfo{
+			Title:       "字节跳动小程序 服务端 API",
+			Description: "由 github.com/fastwego/microapp/cmd 根据接口定义生成",
+			Version:     "1.0.0",
+		},
+		Paths: map[string]map[string]*openapiOperation{},
+		Components: openapiComponents{
+			Schemas: map[string]*openapiSchema{
+				errcodeEnvelope: {
+					Type:        "object",
+					Description: "错误响应，errcode 不为 0 时 表示出错",
+					Properties: map[string]*openapiSchema{
+						"errcode": {Type: "integer", Format: "int64", Description: "错误码"},
+						"errmsg":  {Type: "string", Description: "错误信息"},
+					},
+					Required: []string{"errcode"},
+				},
+				errNoEnvelope: {
+					Type:        "object",
+					Description: "错误响应，err_no 不为 0 时 表示出错",
+					Properties: map[string]*openapiSchema{
+						"err_no":   {Type: "integer", Format: "int64", Description: "错误码"},
+						"err_tips": {Type: "string", Description: "错误信息"},
+					},
+					Required: []string{"err_no"},
+				},
+			},
+			SecuritySchemes: map[string]openapiSecurityScheme{
+				"accessToken": {Type: "apiKey", In: "query", Name: "access_token", Description: "access_token 参数"},
+				"xToken":      {Type: "apiKey", In: "header", Name: "X-Token", Description: "access_token 作为 X-Token 请求头"},
+				"appid":       {Type: "apiKey", In: "query", Name: "appid", Description: "小程序 ID"},
+				"secret":      {Type: "apiKey", In: "query", Name: "secret", Description: "小程序的 APP Secret"},
+			},
+		},
+	}
+
+	for _, group := range groups {
+		doc.Tags = append(doc.Tags, openapiTag{Name: group.Package, Description: group.Name})
+
+		for _, api := range group.Apis {
+			method, link := splitRequest(api.Request)
+			u, err := url.Parse(link)
+			if err != nil {
+				continue
+			}
+
+			if doc.Paths[u.Path] == nil {
+				doc.Paths[u.Path] = map[string]*openapiOperation{}
+			}
+			op := doc.operation(group, api, method)
+			op.Servers = []openapiServer{{URL: u.Scheme + "://" + u.Host}}
+
+			// 同一地址的 JSON 和 文件上传 两种请求方式，合并为一个 operation 的两种 content
+			if existing, ok := doc.Paths[u.Path][strings.ToLower(method)]; ok && existing.RequestBody != nil && op.RequestBody != nil {
+				for contentType, media := range op.RequestBody.Content {
+					if _, ok := existing.RequestBody.Content[contentType]; !ok {
+						existing.RequestBody.Content[contentType] = media
+					}
+				}
+				continue
+			}
+			doc.Paths[u.Path][strings.ToLower(method)] = op
+		}
+	}
+	return doc
+}
+
+// splitRequest 请求方法（去掉 (@media) 等标记） 和 地址
+func splitRequest(request string) (method string, link string) {
+	fields := strings.Fields(request)
+	method = fields[0]
+	if i := strings.Index(method, "("); i > 0 {
+		method = method[:i]
+	}
+	return method, fields[len(fields)-1]
+}
+
+func (doc *openapiDoc) operation(group ApiGroup, api Api, method string) *openapiOperation {
+	op := &openapiOperation{
+		OperationID: group.Package + "." + funcName(api),
+		Summary:     api.Name,
+		Description: api.Description,
+		Tags:        []string{group.Package},
+		Responses:   map[string]openapiResponse{},
+	}
+	if api.See != "" {
+		op.ExternalDocs = &openapiExternalDocs{URL: api.See}
+	}
+
+	for _, param := range api.GetParams {
+		op.Parameters = append(op.Parameters, openapiParameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      doc.fieldSchema(Field{Name: param.Name, Type: param.Type}),
+		})
+	}
+
+	switch api.Auth {
+	case "query":
+		op.Security = []map[string][]string{{"accessToken": {}}}
+	case "header":
+		op.Security = []map[string][]string{{"xToken": {}}}
+		op.Responses["401"] = openapiResponse{Description: "X-Token 无效 或 已过期"}
+	case "secret":
+		op.Security = []map[string][]string{{"appid": {}, "secret": {}}}
+	}
+
+	if method == http.MethodPost {
+		body := doc.objectSchema(api.BodyFields)
+		if api.Auth == "body" {
+			if body.Properties == nil {
+				body.Properties = map[string]*openapiSchema{}
+			}
+			body.Properties["access_token"] = &openapiSchema{Type: "string", Description: "接口调用凭证"}
+			body.Required = append([]string{"access_token"}, body.Required...)
+		}
+		contentType := "application/json"
+		if strings.Contains(api.Request, "(@") {
+			contentType = "multipart/form-data"
+			upload, fields := parseUpload(api.Request)
+			body = &openapiSchema{Type: "object", Properties: map[string]*openapiSchema{
+				upload: {Type: "string", Format: "binary", Description: "上传的文件"},
+			}, Required: []string{upload}}
+			for _, field := range fields {
+				body.Properties[field] = &openapiSchema{Type: "string"}
+			}
+		}
+		op.RequestBody = &openapiRequestBody{
+			Required: true,
+			Content:  map[string]openapiMediaType{contentType: {Schema: body}},
+		}
+	}
+
+	envelope := errcodeEnvelope
+	for _, field := range api.ResponseFields {
+		if field.Name == "err_no" {
+			envelope = errNoEnvelope
+		}
+	}
+
+	success := openapiResponse{Description: "成功"}
+	if len(api.ResponseFields) > 0 {
+		success.Content = map[string]openapiMediaType{"application/json": {Schema: doc.objectSchema(api.ResponseFields)}}
+	} else {
+		success.Content = map[string]openapiMediaType{"*/*": {Schema: &openapiSchema{Type: "string", Format: "binary"}}}
+	}
+	op.Responses["200"] = success
+	op.Responses["default"] = openapiResponse{
+		Description: "错误",
+		Content:     map[string]openapiMediaType{"application/json": {Schema: &openapiSchema{Ref: "#/components/schemas/" + envelope}}},
+	}
+
+	return op
+}
+
+func (doc *openapiDoc) objectSchema(fields []Field) *openapiSchema {
+	schema := &openapiSchema{Type: "object"}
+	for _, field := range fields {
+		if schema.Properties == nil {
+			schema.Properties = map[string]*openapiSchema{}
+		}
+		schema.Properties[field.Name] = doc.fieldSchema(field)
+		if field.Required {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema
+}
+
+// fieldSchema 字段类型 转换为 schema，指定了 TypeName 的对象 放入 components
+func (doc *openapiDoc) fieldSchema(field Field) (schema *openapiSchema) {
+	object := func() *openapiSchema {
+		if field.TypeName == "" {
+			return doc.objectSchema(field.Fields)
+		}
+		if _, ok := doc.Components.Schemas[field.TypeName]; !ok {
+			doc.Components.Schemas[field.TypeName] = doc.objectSchema(field.Fields)
+		}
+		return &openapiSchema{Ref: "#/components/schemas/" + field.TypeName}
+	}
+
+	switch field.Type {
+	case "int":
+		schema = &openapiSchema{Type: "integer", Format: "int32"}
+	case "int64":
+		schema = &openapiSchema{Type: "integer", Format: "int64"}
+	case "float64":
+		schema = &openapiSchema{Type: "number", Format: "double"}
+	case "bool":
+		schema = &openapiSchema{Type: "boolean"}
+	case "[]string":
+		schema = &openapiSchema{Type: "array", Items: &openapiSchema{Type: "string"}}
+	case "map[string]string":
+		schema = &openapiSchema{Type: "object", AdditionalProperties: &openapiSchema{Type: "string"}}
+	case "object":
+		schema = object()
+	case "[]object":
+		schema = &openapiSchema{Type: "array", Items: object()}
+	case "map[string]object":
+		schema = &openapiSchema{Type: "object", AdditionalProperties: object()}
+	default:
+		schema = &openapiSchema{Type: "string"}
+	}
+
+	// $ref 不能有 同级属性
+	if schema.Ref == "" {
+		schema.Description = field.Description
+	}
+	return
+}
+
+func marshalOpenAPI(doc *openapiDoc) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(doc)
+	return buf.Bytes(), err
+}
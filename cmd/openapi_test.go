@@ -0,0 +1,115 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildOpenAPI(t *testing.T) {
+	groups, err := loadSpec("spec/schema.json", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := buildOpenAPI(groups)
+
+	operations := 0
+	for _, methods := range doc.Paths {
+		operations += len(methods)
+	}
+	apis := map[string]bool{}
+	for _, group := range groups {
+		for _, api := range group.Apis {
+			method, link := splitRequest(api.Request)
+			apis[method+" "+link] = true
+		}
+	}
+	if operations != len(apis) {
+		t.Errorf("operations = %d, want %d", operations, len(apis))
+	}
+
+	// JSON 和 文件上传 合并为一个 operation
+	image := doc.Paths["/api/v2/tags/image/"]["post"].RequestBody.Content
+	if image["application/json"].Schema == nil || image["multipart/form-data"].Schema.Properties["image"].Format != "binary" {
+		t.Errorf("image request body = %+v", image)
+	}
+
+	// 按 operation 声明 servers
+	if servers := doc.Paths["/api/apps/jscode2session"]["get"].Servers; !reflect.DeepEqual(servers, []openapiServer{{URL: "https://developer.toutiao.com"}}) {
+		t.Errorf("jscode2session servers = %v", servers)
+	}
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			if strings.HasPrefix(path, "/openapi/") && !reflect.DeepEqual(op.Servers, []openapiServer{{URL: "https://open.microapp.bytedance.com"}}) {
+				t.Errorf("%s %s servers = %v", method, path, op.Servers)
+			}
+		}
+	}
+
+	// access_token 参数
+	storage := doc.Paths["/api/apps/set_user_storage"]["post"]
+	if !reflect.DeepEqual(storage.Security, []map[string][]string{{"accessToken": {}}}) {
+		t.Errorf("set_user_storage security = %v", storage.Security)
+	}
+	if len(storage.Parameters) != 3 || storage.Parameters[0].In != "query" || !storage.Parameters[0].Required {
+		t.Errorf("set_user_storage parameters = %+v", storage.Parameters)
+	}
+
+	// 请求体中的 access_token
+	qrcode := doc.Paths["/api/apps/qrcode"]["post"]
+	body := qrcode.RequestBody.Content["application/json"].Schema
+	if body.Properties["access_token"] == nil || body.Required[0] != "access_token" || qrcode.Security != nil {
+		t.Errorf("qrcode body = %+v", body)
+	}
+	if body.Properties["line_color"].Ref != "#/components/schemas/Color" || doc.Components.Schemas["Color"] == nil {
+		t.Errorf("qrcode line_color = %+v", body.Properties["line_color"])
+	}
+	if qrcode.Responses["200"].Content["*/*"].Schema.Format != "binary" {
+		t.Errorf("qrcode response = %+v", qrcode.Responses["200"])
+	}
+
+	// X-Token 请求头
+	antidirt := doc.Paths["/api/v2/tags/text/antidirt"]["post"]
+	if !reflect.DeepEqual(antidirt.Security, []map[string][]string{{"xToken": {}}}) || antidirt.Responses["401"].Description == "" {
+		t.Errorf("antidirt = %+v", antidirt)
+	}
+
+	// appid/secret 参数
+	session := doc.Paths["/api/apps/jscode2session"]["get"]
+	if !reflect.DeepEqual(session.Security, []map[string][]string{{"appid": {}, "secret": {}}}) || session.RequestBody != nil {
+		t.Errorf("jscode2session = %+v", session)
+	}
+
+	// 错误响应
+	if ref := session.Responses["default"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/"+errcodeEnvelope {
+		t.Errorf("jscode2session error envelope = %s", ref)
+	}
+	notify := doc.Paths["/api/apps/subscribe_notification/developer/v1/notify"]["post"]
+	if ref := notify.Responses["default"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/"+errNoEnvelope {
+		t.Errorf("notify error envelope = %s", ref)
+	}
+
+	output, err := marshalOpenAPI(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err = json.Unmarshal(output, &decoded); err != nil || decoded["openapi"] != "3.0.3" {
+		t.Errorf("marshalOpenAPI() = %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+jsonSchema JSON Schema 校验
+
+只支持 spec/schema.json 用到的关键字：
+$ref（#/definitions/xxx） type properties required additionalProperties items enum pattern minLength
+*/
+type jsonSchema struct {
+	root map[string]interface{}
+}
+
+func parseJSONSchema(data []byte) (schema *jsonSchema, err error) {
+	schema = &jsonSchema{}
+	if err = json.Unmarshal(data, &schema.root); err != nil {
+		return nil, fmt.Errorf("parse schema: %v", err)
+	}
+	return
+}
+
+// Validate 校验 JSON 文档，返回全部错误
+func (schema *jsonSchema) Validate(data []byte) (errs []string) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []string{err.Error()}
+	}
+	return schema.validate(schema.root, doc, "")
+}
+
+func (schema *jsonSchema) validate(node map[string]interface{}, value interface{}, path string) (errs []string) {
+	fail := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "(root)"
+		}
+		errs = append(errs, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	if ref, ok := node["$ref"].(string); ok {
+		resolved, err := schema.resolve(ref)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		return schema.validate(resolved, value, path)
+	}
+
+	if expected, ok := node["type"]; ok && !matchType(expected, value) {
+		fail("expected %v, got %s", expected, jsonType(value))
+		return
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+			}
+		}
+		if !found {
+			fail("%v is not one of %v", value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if min, ok := node["minLength"].(float64); ok && utf8.RuneCountInString(v) < int(min) {
+			fail("shorter than %v", min)
+		}
+		if pattern, ok := node["pattern"].(string); ok {
+			reg, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %s: %v", pattern, err)
+			} else if !reg.MatchString(v) {
+				fail("%q does not match %s", v, pattern)
+			}
+		}
+	case []interface{}:
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, schema.validate(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		properties, _ := node["properties"].(map[string]interface{})
+		if required, ok := node["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					fail("missing required property %s", name)
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := strings.TrimPrefix(path+"."+key, ".")
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				errs = append(errs, schema.validate(property, v[key], child)...)
+			} else if additional, ok := node["additionalProperties"].(bool); ok && !additional {
+				fail("unknown property %s", key)
+			}
+		}
+	}
+	return
+}
+
+func (schema *jsonSchema) resolve(ref string) (node map[string]interface{}, err error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %s", ref)
+	}
+	var current interface{} = schema.root
+	for _, name := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolved $ref %s", ref)
+		}
+		current = object[name]
+	}
+	if node, ok := current.(map[string]interface{}); ok {
+		return node, nil
+	}
+	return nil, fmt.Errorf("unresolved $ref %s", ref)
+}
+
+func matchType(expected interface{}, value interface{}) bool {
+	switch t := expected.(type) {
+	case string:
+		actual := jsonType(value)
+		return actual == t || (t == "number" && actual == "integer")
+	case []interface{}:
+		for _, candidate := range t {
+			if matchType(candidate, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,567 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/iancoleman/strcase"
+)
+
+/*
+scrapeMain 离线解析 保存的服务端文档页面，输出 apiConfig
+
+	go run . scrape -dir pages -out spec/scraped.json
+	go run . scrape -dir pages -out scraped.go
+
+dir 下的子目录名 作为 包名，直接位于 dir 下的页面 归入 -package 指定的包
+*/
+func scrapeMain(args []string) int {
+	flags := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	dir := flags.String("dir", "", "保存的文档页面目录")
+	out := flags.String("out", "-", "输出文件，- 为标准输出")
+	format := flags.String("format", "", "输出格式 json/go，默认根据 -out 扩展名判断")
+	varName := flags.String("var", "scrapedApiConfig", "生成 Go 代码时的变量名")
+	pkg := flags.String("package", "scraped", "直接位于 dir 下的页面 归入的包")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *dir == "" {
+		fmt.Fprintln(os.Stderr, "scrape: -dir is required")
+		flags.Usage()
+		return 2
+	}
+
+	groups, skipped, err := scrapeDir(*dir, *pkg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scrape:", err)
+		return 1
+	}
+	for _, reason := range skipped {
+		fmt.Fprintln(os.Stderr, "skip:", reason)
+	}
+
+	if *format == "" {
+		*format = "json"
+		if strings.HasSuffix(*out, ".go") {
+			*format = "go"
+		}
+	}
+
+	var output []byte
+	switch *format {
+	case "go":
+		output, err = renderApiConfig(*varName, groups)
+	case "json":
+		output, err = marshalSpec(groups)
+	default:
+		err = fmt.Errorf("unknown format %s", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scrape:", err)
+		return 1
+	}
+
+	if *out == "-" {
+		_, err = os.Stdout.Write(output)
+	} else {
+		err = ioutil.WriteFile(*out, output, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scrape:", err)
+		return 1
+	}
+	return 0
+}
+
+/*
+scrapeDir 解析 dir 下的全部 .html 页面
+
+按 请求方法 + 地址 去重，保留 路径排序靠前的页面；无法解析 或 重复的页面 记录在 skipped 中
+*/
+func scrapeDir(dir string, defaultPackage string) (groups []ApiGroup, skipped []string, err error) {
+	var pages []string
+	err = filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(filename, ".html") {
+			pages = append(pages, filename)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	sort.Strings(pages)
+
+	seen := map[string]string{}
+	index := map[string]int{}
+	for _, page := range pages {
+		rel, _ := filepath.Rel(dir, page)
+
+		content, err := ioutil.ReadFile(page)
+		if err != nil {
+			return nil, nil, err
+		}
+		api, err := scrapePage(string(content))
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+
+		key := requestKey(api.Request)
+		if first, ok := seen[key]; ok {
+			skipped = append(skipped, fmt.Sprintf("%s: duplicate of %s", rel, first))
+			continue
+		}
+		seen[key] = rel
+
+		pkg := defaultPackage
+		if sub := filepath.Dir(rel); sub != "." {
+			pkg = strcase.ToSnake(filepath.ToSlash(sub)[strings.LastIndex(filepath.ToSlash(sub), "/")+1:])
+		}
+		i, ok := index[pkg]
+		if !ok {
+			i = len(groups)
+			index[pkg] = i
+			groups = append(groups, ApiGroup{Name: pkg, Package: pkg})
+		}
+		groups[i].Apis = append(groups[i].Apis, api)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Package < groups[j].Package
+	})
+	for i := range groups {
+		uniqueFuncNames(groups[i].Apis)
+	}
+	return
+}
+
+// requestKey 去掉 query 的 请求方法 + 地址
+func requestKey(request string) string {
+	fields := strings.Fields(request)
+	if len(fields) < 2 {
+		return request
+	}
+	return fields[0] + " " + strings.SplitN(fields[1], "?", 2)[0]
+}
+
+// uniqueFuncNames 包内 方法名 重复时 加上 上一级路径
+func uniqueFuncNames(apis []Api) {
+	count := map[string]int{}
+	for _, api := range apis {
+		count[api.FuncName]++
+	}
+	for i, api := range apis {
+		if count[api.FuncName] < 2 {
+			continue
+		}
+		u, _ := url.Parse(strings.Fields(api.Request)[1])
+		apis[i].FuncName = strcase.ToCamel(path.Base(path.Dir(u.Path))) + api.FuncName
+	}
+}
+
+var requestPattern = regexp.MustCompile(`\b(GET|POST|PUT|DELETE)\s+(https?://[^\s"'<]+)`)
+
+// scrapePage 解析 单个 文档页面
+func scrapePage(html string) (api Api, err error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return
+	}
+
+	content := doc.Find(".markdown-render-content")
+	if content.Length() == 0 {
+		content = doc.Find("body")
+	}
+
+	h1 := content.Find("h1").First()
+	api.Name = cleanText(h1.Text())
+	api.Description = cleanText(sectionText(h1))
+	api.See, _ = doc.Find(`link[rel="canonical"]`).Attr("href")
+	if api.See == "" {
+		api.See, _ = doc.Find(`meta[property="og:url"]`).Attr("content")
+	}
+
+	var tables []scrapedTable
+	var h2, h3 string
+	content.Find("h2, h3, h4, table").Each(func(_ int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "h2":
+			h2, h3 = cleanText(s.Text()), ""
+			if strings.Contains(h2, "请求地址") {
+				if match := requestPattern.FindStringSubmatch(sectionText(s)); match != nil {
+					api.Request = match[1] + " " + match[2]
+				}
+			}
+		case "h3", "h4":
+			h3 = cleanText(s.Text())
+		case "table":
+			tables = append(tables, scrapedTable{section: h2, sub: h3, rows: tableRows(s)})
+		}
+	})
+
+	if api.Name == "" {
+		return api, errors.New("missing h1 title")
+	}
+	if api.Request == "" {
+		return api, errors.New("missing request line")
+	}
+
+	fields := strings.Fields(api.Request)
+	isGet := fields[0] == "GET"
+	u, err := url.Parse(fields[1])
+	if err != nil {
+		return
+	}
+	api.FuncName = strcase.ToCamel(path.Base(u.Path))
+
+	var query []Param
+	for _, name := range sortedKeys(u.Query()) {
+		query = append(query, Param{Name: name, Type: "string"})
+	}
+	api.Request = fields[0] + " " + strings.SplitN(fields[1], "?", 2)[0]
+
+	for _, table := range tables {
+		parsed := parseFieldTable(table.rows)
+		section := strings.ToLower(table.section + " " + table.sub)
+		switch {
+		case strings.Contains(section, "返回") || strings.Contains(section, "响应") || strings.Contains(section, "response"):
+			api.ResponseFields = append(api.ResponseFields, parsed...)
+		case strings.Contains(section, "header") || strings.Contains(section, "请求头"):
+			for _, field := range parsed {
+				if strings.EqualFold(field.Name, "X-Token") {
+					api.Auth = "header"
+				}
+			}
+		case strings.Contains(section, "参数") || strings.Contains(section, "请求") || strings.Contains(section, "query") || strings.Contains(section, "body"):
+			if strings.Contains(section, "query") || (isGet && !strings.Contains(section, "body")) {
+				query = mergeParams(query, parsed)
+			} else {
+				api.BodyFields = append(api.BodyFields, parsed...)
+			}
+		}
+	}
+
+	api.GetParams, api.BodyFields = detectAuth(&api, query, api.BodyFields)
+	return
+}
+
+type scrapedTable struct {
+	section string
+	sub     string
+	rows    [][]string
+}
+
+// sectionText 标题之后 到 下一个标题之前的 文本
+func sectionText(heading *goquery.Selection) string {
+	var texts []string
+	heading.NextUntil("h1, h2, h3").Each(func(_ int, s *goquery.Selection) {
+		texts = append(texts, s.Text())
+	})
+	return strings.Join(texts, "\n")
+}
+
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// tableRows 表格内容，第一行为表头
+func tableRows(table *goquery.Selection) (rows [][]string) {
+	table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		var cells []string
+		tr.Find("th, td").Each(func(_ int, cell *goquery.Selection) {
+			cells = append(cells, strings.TrimSpace(cell.Text()))
+		})
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	})
+	return
+}
+
+// columnIndex 根据表头关键字 查找列
+func columnIndex(header []string, keywords ...string) int {
+	for i, title := range header {
+		title = strings.ToLower(title)
+		for _, keyword := range keywords {
+			if strings.Contains(title, keyword) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var childMarker = regexp.MustCompile(`^[\s└├─\-—>]+`)
+
+/*
+parseFieldTable 解析 参数表格
+
+嵌套字段 使用 a.b 形式 或 以 └ / - 开头（每个标记 增加一层）
+*/
+func parseFieldTable(rows [][]string) (fields []Field) {
+	if len(rows) < 2 {
+		return
+	}
+	header := rows[0]
+	nameIndex := columnIndex(header, "名称", "参数", "字段", "name", "key")
+	typeIndex := columnIndex(header, "类型", "type")
+	requiredIndex := columnIndex(header, "必填", "必须", "required")
+	descIndex := columnIndex(header, "描述", "说明", "备注", "description")
+	if nameIndex < 0 {
+		nameIndex = 0
+	}
+
+	cell := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	// stack[depth] 当前层级 最近的字段
+	var stack []*[]Field
+	stack = append(stack, &fields)
+	for _, row := range rows[1:] {
+		name := cell(row, nameIndex)
+		depth := 0
+		if marker := childMarker.FindString(name); marker != "" {
+			depth = strings.Count(marker, "└") + strings.Count(marker, "├") + strings.Count(marker, "-") + strings.Count(marker, "—") + strings.Count(marker, ">")
+			name = strings.TrimSpace(name[len(marker):])
+		}
+		if parts := strings.Split(name, "."); len(parts) > 1 {
+			depth = len(parts) - 1
+			name = parts[len(parts)-1]
+		}
+		name = strings.TrimSuffix(strings.TrimSpace(name), "[]")
+		if name == "" {
+			continue
+		}
+
+		required := cell(row, requiredIndex)
+		field := Field{
+			Name:        name,
+			Type:        scrapeType(cell(row, typeIndex)),
+			Required:    required == "是" || strings.EqualFold(required, "true") || strings.EqualFold(required, "yes") || strings.EqualFold(required, "y") || required == "必填",
+			Description: cleanText(cell(row, descIndex)),
+		}
+
+		if depth >= len(stack) {
+			depth = len(stack) - 1
+		}
+		stack = stack[:depth+1]
+		parent := stack[depth]
+		*parent = append(*parent, field)
+		last := &(*parent)[len(*parent)-1]
+		stack = append(stack, &last.Fields)
+	}
+
+	fixObjectTypes(fields)
+	return
+}
+
+// fixObjectTypes 有子字段的 为对象，没有子字段的对象 退化为 map/[]string
+func fixObjectTypes(fields []Field) {
+	for i := range fields {
+		field := &fields[i]
+		fixObjectTypes(field.Fields)
+		switch {
+		case len(field.Fields) > 0 && strings.HasPrefix(field.Type, "[]"):
+			field.Type = "[]object"
+		case len(field.Fields) > 0 && field.Type != "map[string]object":
+			field.Type = "object"
+		case field.Type == "object" || field.Type == "map[string]object":
+			field.Type = "map[string]string"
+		case field.Type == "[]object":
+			field.Type = "[]string"
+		}
+	}
+}
+
+// scrapeType 文档中的类型 转换为 Field.Type
+func scrapeType(raw string) string {
+	t := strings.ToLower(strings.Join(strings.Fields(raw), ""))
+	switch {
+	case strings.Contains(t, "[]object"), strings.Contains(t, "object[]"), strings.Contains(t, "array<object>"), strings.Contains(t, "list<object>"):
+		return "[]object"
+	case strings.Contains(t, "array"), strings.Contains(t, "list"), strings.HasPrefix(t, "[]"), strings.HasSuffix(t, "[]"):
+		return "[]string"
+	case strings.Contains(t, "map"):
+		return "map[string]string"
+	case strings.Contains(t, "object"), strings.Contains(t, "json"), strings.Contains(t, "struct"):
+		return "object"
+	case strings.Contains(t, "int64"), strings.Contains(t, "long"):
+		return "int64"
+	case strings.Contains(t, "int"), strings.Contains(t, "number"):
+		return "int"
+	case strings.Contains(t, "float"), strings.Contains(t, "double"):
+		return "float64"
+	case strings.Contains(t, "bool"):
+		return "bool"
+	}
+	return "string"
+}
+
+// mergeParams 合并 地址中的参数 和 表格中的参数，以表格的顺序和描述为准
+func mergeParams(params []Param, fields []Field) (merged []Param) {
+	inTable := map[string]bool{}
+	for _, field := range fields {
+		param := Param{Name: field.Name, Type: field.Type, Required: field.Required, Description: field.Description}
+		if strings.HasPrefix(param.Type, "[]") || strings.HasPrefix(param.Type, "map") || param.Type == "object" {
+			param.Type = "string"
+		}
+		merged = append(merged, param)
+		inTable[param.Name] = true
+	}
+	for _, param := range params {
+		if !inTable[param.Name] {
+			merged = append(merged, param)
+		}
+	}
+	return
+}
+
+// detectAuth 根据 access_token/appid/secret 所在位置 判断鉴权方式，并从参数中去掉
+func detectAuth(api *Api, query []Param, body []Field) ([]Param, []Field) {
+	hasParam := func(name string) bool {
+		for _, param := range query {
+			if param.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var drop map[string]bool
+	switch {
+	case api.Auth == "header":
+	case hasParam("access_token"):
+		api.Auth = "query"
+		drop = map[string]bool{"access_token": true}
+	case hasParam("appid") && hasParam("secret"):
+		api.Auth = "secret"
+		drop = map[string]bool{"appid": true, "secret": true}
+	default:
+		for i, field := range body {
+			if field.Name == "access_token" {
+				api.Auth = "body"
+				body = append(body[:i:i], body[i+1:]...)
+				break
+			}
+		}
+	}
+
+	var params []Param
+	for _, param := range query {
+		if !drop[param.Name] {
+			params = append(params, param)
+		}
+	}
+	return params, body
+}
+
+func sortedKeys(values url.Values) (keys []string) {
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// renderApiConfig 输出 apiConfig 形式的 Go 代码
+func renderApiConfig(varName string, groups []ApiGroup) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("package main\n\n// " + varName + " 由 `go run . scrape` 根据文档页面生成\n")
+	b.WriteString("var " + varName + " = []ApiGroup{\n")
+	for _, group := range groups {
+		b.WriteString("{\n")
+		fmt.Fprintf(&b, "Name: %s,\nPackage: %s,\nApis: []Api{\n", strconv.Quote(group.Name), strconv.Quote(group.Package))
+		for _, api := range group.Apis {
+			b.WriteString("{\n")
+			fmt.Fprintf(&b, "Name: %s,\n", strconv.Quote(api.Name))
+			fmt.Fprintf(&b, "Description: %s,\n", strconv.Quote(api.Description))
+			fmt.Fprintf(&b, "Request: %s,\n", strconv.Quote(api.Request))
+			fmt.Fprintf(&b, "See: %s,\n", strconv.Quote(api.See))
+			fmt.Fprintf(&b, "FuncName: %s,\n", strconv.Quote(api.FuncName))
+			if len(api.GetParams) > 0 {
+				b.WriteString("GetParams: []Param{\n")
+				for _, param := range api.GetParams {
+					fmt.Fprintf(&b, "{Name: %s, Type: %s", strconv.Quote(param.Name), strconv.Quote(param.Type))
+					if param.Required {
+						b.WriteString(", Required: true")
+					}
+					if param.Description != "" {
+						fmt.Fprintf(&b, ", Description: %s", strconv.Quote(param.Description))
+					}
+					b.WriteString("},\n")
+				}
+				b.WriteString("},\n")
+			}
+			if api.Auth != "" {
+				fmt.Fprintf(&b, "Auth: %s,\n", strconv.Quote(api.Auth))
+			}
+			renderFields(&b, "BodyFields", api.BodyFields)
+			renderFields(&b, "ResponseFields", api.ResponseFields)
+			b.WriteString("},\n")
+		}
+		b.WriteString("},\n},\n")
+	}
+	b.WriteString("}\n")
+
+	return formatSource(b.String(), nil)
+}
+
+func renderFields(b *strings.Builder, key string, fields []Field) {
+	if len(fields) == 0 {
+		return
+	}
+	if key != "" {
+		b.WriteString(key + ": ")
+	}
+	b.WriteString("[]Field{\n")
+	for _, field := range fields {
+		fmt.Fprintf(b, "{Name: %s, Type: %s", strconv.Quote(field.Name), strconv.Quote(field.Type))
+		if field.Required {
+			b.WriteString(", Required: true")
+		}
+		if field.Description != "" {
+			fmt.Fprintf(b, ", Description: %s", strconv.Quote(field.Description))
+		}
+		if len(field.Fields) > 0 {
+			b.WriteString(", Fields: ")
+			renderFields(b, "", field.Fields)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}")
+	if key != "" {
+		b.WriteString(",\n")
+	}
+}
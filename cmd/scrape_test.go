@@ -0,0 +1,170 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func scrapeFixtures(t *testing.T) []ApiGroup {
+	groups, skipped, err := scrapeDir(filepath.Join("testdata", "scrape"), "scraped")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSkipped := []string{
+		"broken.html: missing request line",
+		filepath.Join("data_caching", "set_user_storage_v1.html") + ": duplicate of " + filepath.Join("data_caching", "set_user_storage.html"),
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %q, want %q", skipped, wantSkipped)
+	}
+	return groups
+}
+
+func findApi(t *testing.T, groups []ApiGroup, pkg string, funcName string) Api {
+	for _, group := range groups {
+		if group.Package != pkg {
+			continue
+		}
+		for _, api := range group.Apis {
+			if api.FuncName == funcName {
+				return api
+			}
+		}
+	}
+	t.Fatalf("%s.%s not scraped", pkg, funcName)
+	return Api{}
+}
+
+func TestScrapeDir(t *testing.T) {
+	groups := scrapeFixtures(t)
+
+	var packages []string
+	for _, group := range groups {
+		packages = append(packages, group.Package)
+	}
+	if got := strings.Join(packages, ","); got != "auth,content_security,data_caching,qrcode,scraped" {
+		t.Fatalf("packages = %s", got)
+	}
+
+	session := findApi(t, groups, "auth", "Jscode2Session")
+	if session.Auth != "secret" || session.Request != "GET https://developer.toutiao.com/api/apps/jscode2session" {
+		t.Errorf("code2Session = %+v", session)
+	}
+	if len(session.GetParams) != 2 || session.GetParams[0].Name != "code" || session.GetParams[1].Name != "anonymous_code" {
+		t.Errorf("code2Session GetParams = %+v", session.GetParams)
+	}
+	if session.See != "https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/log-in/code-2-session" {
+		t.Errorf("code2Session See = %s", session.See)
+	}
+
+	storage := findApi(t, groups, "data_caching", "SetUserStorage")
+	if storage.Auth != "query" || len(storage.GetParams) != 3 || !storage.GetParams[0].Required {
+		t.Errorf("setUserStorage = %+v", storage)
+	}
+	wantKv := Field{Name: "kv_list", Type: "[]object", Required: true, Description: "需要设置的数据", Fields: []Field{
+		{Name: "key", Type: "string", Required: true, Description: "数据的 key"},
+		{Name: "value", Type: "string", Required: true, Description: "数据的 value"},
+	}}
+	if len(storage.BodyFields) != 1 || !reflect.DeepEqual(storage.BodyFields[0], wantKv) {
+		t.Errorf("setUserStorage BodyFields = %+v", storage.BodyFields)
+	}
+
+	qrcode := findApi(t, groups, "qrcode", "Qrcode")
+	if qrcode.Auth != "body" || len(qrcode.BodyFields) != 5 || len(qrcode.ResponseFields) != 0 {
+		t.Errorf("createQRCode = %+v", qrcode)
+	}
+	if color := qrcode.BodyFields[3]; color.Type != "object" || len(color.Fields) != 2 {
+		t.Errorf("line_color = %+v", color)
+	}
+
+	antidirt := findApi(t, groups, "content_security", "Antidirt")
+	if antidirt.Auth != "header" || antidirt.Name != "内容安全检测" {
+		t.Errorf("antidirt = %+v", antidirt)
+	}
+	predicts := antidirt.ResponseFields[1].Fields[1]
+	if predicts.Name != "predicts" || predicts.Type != "[]object" || len(predicts.Fields) != 2 {
+		t.Errorf("predicts = %+v", predicts)
+	}
+
+	ping := findApi(t, groups, "scraped", "Ping")
+	if ping.Auth != "" || ping.Description != "连通性检测。" {
+		t.Errorf("ping = %+v", ping)
+	}
+}
+
+func TestScrapeOutput(t *testing.T) {
+	groups := scrapeFixtures(t)
+
+	code, err := renderApiConfig("scrapedApiConfig", groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), "scraped.go", code, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(code), `{Name: "kv_list", Type: "[]object", Required: true, Description: "需要设置的数据", Fields: []Field{`) {
+		t.Errorf("renderApiConfig() = %s", code)
+	}
+
+	out := filepath.Join(t.TempDir(), "scraped.json")
+	if status := scrapeMain([]string{"-dir", filepath.Join("testdata", "scrape"), "-out", out}); status != 0 {
+		t.Fatalf("scrapeMain() = %d", status)
+	}
+	content, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded specFile
+	if err = json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Groups, groups) {
+		t.Errorf("json output = %s", content)
+	}
+
+	// 输出的接口定义 可以直接放入 spec 目录
+	if _, err = loadSpec(filepath.Join("spec", "schema.json"), out); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScrapeType(t *testing.T) {
+	tests := map[string]string{
+		"String":        "string",
+		"number":        "int",
+		"int64":         "int64",
+		"Float":         "float64",
+		"Boolean":       "bool",
+		"Object":        "object",
+		"array<object>": "[]object",
+		"Array<String>": "[]string",
+		"map":           "map[string]string",
+		"":              "string",
+	}
+	for raw, want := range tests {
+		if got := scrapeType(raw); got != want {
+			t.Errorf("scrapeType(%q) = %s, want %s", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// specFile 接口定义文件
+type specFile struct {
+	Schema string     `json:"$schema,omitempty"`
+	Groups []ApiGroup `json:"groups"`
+}
+
+/*
+loadSpec 加载 接口定义
+
+paths 可以是 文件 或 目录（目录下的全部 .json 文件，schema.json 除外），
+每个文件 先使用 schemaFile 校验，再按 包名 合并：同一个包的接口 可以分散在多个文件中，例如 私有接口 单独存放
+*/
+func loadSpec(schemaFile string, paths ...string) (groups []ApiGroup, err error) {
+	schemaData, err := ioutil.ReadFile(schemaFile)
+	if err != nil {
+		return
+	}
+	schema, err := parseJSONSchema(schemaData)
+	if err != nil {
+		return
+	}
+
+	files, err := specFiles(paths)
+	if err != nil {
+		return
+	}
+
+	index := map[string]int{}
+	requests := map[string]string{}
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if errs := schema.Validate(data); len(errs) > 0 {
+			return nil, fmt.Errorf("%s:\n\t%s", filename, strings.Join(errs, "\n\t"))
+		}
+
+		var spec specFile
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+
+		for _, group := range spec.Groups {
+			for _, api := range group.Apis {
+				key := group.Package + " " + requestKey(api.Request)
+				if first, ok := requests[key]; ok {
+					return nil, fmt.Errorf("%s: %s %s already defined in %s", filename, group.Package, api.Request, first)
+				}
+				requests[key] = filename
+			}
+
+			i, ok := index[group.Package]
+			if !ok {
+				index[group.Package] = len(groups)
+				groups = append(groups, group)
+				continue
+			}
+			if group.Name != "" {
+				if groups[i].Name != "" && groups[i].Name != group.Name {
+					return nil, fmt.Errorf("%s: package %s named %s, already named %s", filename, group.Package, group.Name, groups[i].Name)
+				}
+				groups[i].Name = group.Name
+			}
+			groups[i].Apis = append(groups[i].Apis, group.Apis...)
+		}
+	}
+
+	for _, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("package %s has no name", group.Package)
+		}
+	}
+	return
+}
+
+// specFiles 展开目录，按路径排序
+func specFiles(paths []string) (files []string, err error) {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matched, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matched)
+		for _, filename := range matched {
+			if filepath.Base(filename) != "schema.json" {
+				files = append(files, filename)
+			}
+		}
+	}
+	return
+}
+
+// marshalSpec 输出 接口定义文件
+func marshalSpec(groups []ApiGroup) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(specFile{Schema: "./schema.json", Groups: groups})
+	return buf.Bytes(), err
+}
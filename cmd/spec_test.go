@@ -0,0 +1,179 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// spec 目录下的文件 应与 marshalSpec 的输出一致，避免手工编辑引入格式差异
+func TestSpecFormat(t *testing.T) {
+	files, err := specFiles([]string{"spec"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no spec files")
+	}
+
+	for _, filename := range files {
+		groups, err := loadSpec("spec/schema.json", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := marshalSpec(groups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			line, gotLine, wantLine := firstDiff(got, want)
+			t.Errorf("%s:%d not formatted\n got: %s\n want: %s", filename, line, gotLine, wantLine)
+		}
+	}
+}
+
+func writeSpec(t *testing.T, dir string, name string, content string) string {
+	filename := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadSpecMerge(t *testing.T) {
+	private := t.TempDir()
+	writeSpec(t, private, "qrcode_private.json", `{
+  "groups": [
+    {
+      "package": "qrcode",
+      "apis": [
+        {
+          "name": "internalQRCode",
+          "description": "内部接口",
+          "request": "POST https://developer.toutiao.com/api/apps/internal/qrcode",
+          "see": "",
+          "auth": "body"
+        }
+      ]
+    },
+    {
+      "name": "内部",
+      "package": "internal",
+      "apis": []
+    }
+  ]
+}`)
+
+	groups, err := loadSpec("spec/schema.json", "spec", private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var qrcode, internal *ApiGroup
+	for i := range groups {
+		switch groups[i].Package {
+		case "qrcode":
+			qrcode = &groups[i]
+		case "internal":
+			internal = &groups[i]
+		}
+	}
+	if qrcode == nil || qrcode.Name != "二维码" || len(qrcode.Apis) != 2 || qrcode.Apis[1].Name != "internalQRCode" {
+		t.Errorf("merged qrcode = %+v", qrcode)
+	}
+	if internal == nil || internal.Name != "内部" {
+		t.Errorf("internal = %+v", internal)
+	}
+}
+
+func TestLoadSpecInvalid(t *testing.T) {
+	tests := map[string]struct {
+		spec string
+		want string
+	}{
+		"schema": {
+			spec: `{"groups": [{"name": "演示", "package": "demo", "apis": [{"name": "a", "description": "", "request": "PUT https://example.com/a", "see": "", "auth": "cookie", "extra": 1}]}]}`,
+			want: `groups[0].apis[0].auth: cookie is not one of`,
+		},
+		"required": {
+			spec: `{"groups": [{"name": "演示", "package": "demo", "apis": [{"name": "a", "request": "POST https://example.com/a", "see": "", "body_fields": [{"name": "b", "type": "uint"}]}]}]}`,
+			want: `groups[0].apis[0]: missing required property description`,
+		},
+		"duplicate": {
+			spec: `{"groups": [{"name": "二维码", "package": "qrcode", "apis": [{"name": "a", "description": "", "request": "POST https://developer.toutiao.com/api/apps/qrcode", "see": ""}]}]}`,
+			want: `already defined in`,
+		},
+		"name": {
+			spec: `{"groups": [{"name": "其他", "package": "qrcode", "apis": []}]}`,
+			want: `package qrcode named 其他, already named 二维码`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := writeSpec(t, t.TempDir(), "zz.json", tt.spec)
+			_, err := loadSpec("spec/schema.json", "spec", filename)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadSpec() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := parseJSONSchema([]byte(`{
+  "type": "object",
+  "required": ["id"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "integer"},
+    "tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}
+  },
+  "definitions": {
+    "tag": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := schema.Validate([]byte(`{"id": 1, "tags": ["ab", "cd"]}`)); len(errs) != 0 {
+		t.Errorf("Validate(valid) = %v", errs)
+	}
+
+	got := strings.Join(schema.Validate([]byte(`{"id": 1.5, "tags": ["a", "B2"], "name": "x"}`)), "\n")
+	for _, want := range []string{
+		"id: expected integer, got number",
+		"tags[0]: shorter than 2",
+		`tags[1]: "B2" does not match ^[a-z]+$`,
+		"(root): unknown property name",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Validate() = %s, want %s", got, want)
+		}
+	}
+
+	if errs := schema.Validate([]byte(`[]`)); len(errs) != 1 || errs[0] != "(root): expected object, got array" {
+		t.Errorf("Validate(array) = %v", errs)
+	}
+}
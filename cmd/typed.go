@@ -0,0 +1,702 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// typedApi 是否为 api 生成 类型化的请求/响应 和 调用方法
+func typedApi(api Api) bool {
+	if api.Handwritten || strings.Contains(api.Request, "POST(@") {
+		return false
+	}
+	return len(api.GetParams) > 0 || len(api.BodyFields) > 0 || len(api.ResponseFields) > 0
+}
+
+// 使用 session_key 签名的接口 由 类型化方法 自动填充的 query 参数
+var sessionSignParams = map[string]bool{"signature": true, "sig_method": true}
+
+// requestParams 类型化请求结构体中的 query 参数，不含 自动填充的签名参数
+func requestParams(api Api) (params []Param) {
+	for _, param := range api.GetParams {
+		if api.Sign == "session_key" && sessionSignParams[param.Name] {
+			continue
+		}
+		params = append(params, param)
+	}
+	return
+}
+
+// funcName api 对应的方法名
+func funcName(api Api) string {
+	if api.FuncName != "" {
+		return api.FuncName
+	}
+
+	split := strings.Split(api.Request, " ")
+	parseUrl, _ := url.Parse(split[1])
+	return strcase.ToCamel(path.Base(parseUrl.Path))
+}
+
+// fieldName 字段名 转为 Go 结构体字段名
+func fieldName(name string) string {
+	return strcase.ToCamel(name)
+}
+
+/*
+typedBuilder 生成 类型化的请求/响应 结构体
+
+嵌套结构体 按 TypeName 去重，请求侧的结构体 附带 Validate 方法
+*/
+type typedBuilder struct {
+	structs []string
+	defined map[string]bool
+}
+
+func newTypedBuilder() *typedBuilder {
+	return &typedBuilder{defined: map[string]bool{}}
+}
+
+// needValidate 字段（含嵌套字段）中是否有需要校验的必填项
+func needValidate(fields []Field) bool {
+	for _, field := range fields {
+		if field.Required && !field.AllowEmpty && zeroCheck(field.Type, field.Name) != "" {
+			return true
+		}
+		if needValidate(field.Fields) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedName 嵌套结构体名称
+func nestedName(parent string, field Field) string {
+	if field.TypeName != "" {
+		return field.TypeName
+	}
+	return parent + fieldName(field.Name)
+}
+
+// goType 字段的 Go 类型，嵌套结构体 同时生成定义
+func (builder *typedBuilder) goType(parent string, field Field, request bool) string {
+	switch field.Type {
+	case "object":
+		name := builder.nested(parent, field, request)
+		if field.Required {
+			return name
+		}
+		return "*" + name
+	case "[]object":
+		return "[]" + builder.nested(parent, field, request)
+	case "map[string]object":
+		return "map[string]" + builder.nested(parent, field, request)
+	default:
+		return field.Type
+	}
+}
+
+func (builder *typedBuilder) nested(parent string, field Field, request bool) string {
+	name := nestedName(parent, field)
+	if builder.defined[name] {
+		return name
+	}
+	builder.defined[name] = true
+
+	comment := field.Description
+	if comment == "" {
+		comment = field.Name
+	}
+	builder.structDef(name, comment, nil, field.Fields, request)
+	if request && needValidate(field.Fields) {
+		builder.structs = append(builder.structs, validateDef(name, nil, field.Fields))
+	}
+	return name
+}
+
+// structDef 生成 结构体定义
+func (builder *typedBuilder) structDef(name string, comment string, params []Param, fields []Field, request bool) {
+	var lines []string
+	for _, param := range params {
+		lines = append(lines, fmt.Sprintf("\t%s %s `json:\"-\"`%s", fieldName(param.Name), param.Type, lineComment(param.Description)))
+	}
+	for _, field := range fields {
+		tag := field.Name
+		if request && !field.Required {
+			tag += ",omitempty"
+		}
+		lines = append(lines, fmt.Sprintf("\t%s %s `json:\"%s\"`%s", fieldName(field.Name), builder.goType(name, field, request), tag, lineComment(field.Description)))
+	}
+
+	builder.structs = append(builder.structs, fmt.Sprintf("\n// %s %s\ntype %s struct {\n%s\n}\n", name, comment, name, strings.Join(lines, "\n")))
+}
+
+func lineComment(description string) string {
+	if description == "" {
+		return ""
+	}
+	return " // " + description
+}
+
+// validateDef 生成 Validate 方法，校验必填参数
+func validateDef(name string, params []Param, fields []Field) string {
+	var checks []string
+
+	for _, param := range params {
+		if !param.Required {
+			continue
+		}
+		if zero := zeroCheck(param.Type, "req."+fieldName(param.Name)); zero != "" {
+			checks = append(checks, fmt.Sprintf("\tif %s {\n\t\treturn errors.New(\"%s is required\")\n\t}", zero, param.Name))
+		}
+	}
+
+	for _, field := range fields {
+		expr := "req." + fieldName(field.Name)
+		if field.Required && !field.AllowEmpty {
+			if zero := zeroCheck(field.Type, expr); zero != "" {
+				checks = append(checks, fmt.Sprintf("\tif %s {\n\t\treturn errors.New(\"%s is required\")\n\t}", zero, field.Name))
+			}
+		}
+		if !needValidate(field.Fields) {
+			continue
+		}
+		switch field.Type {
+		case "object":
+			check := fmt.Sprintf("if err := %s.Validate(); err != nil {\n\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t}", expr, field.Name)
+			if !field.Required {
+				check = fmt.Sprintf("if %s != nil {\n\t\t%s\n\t}", expr, strings.ReplaceAll(check, "\n", "\n\t"))
+			}
+			checks = append(checks, "\t"+check)
+		case "[]object":
+			checks = append(checks, fmt.Sprintf("\tfor i, item := range %s {\n\t\tif err := item.Validate(); err != nil {\n\t\t\treturn fmt.Errorf(\"%s[%%d]: %%w\", i, err)\n\t\t}\n\t}", expr, field.Name))
+		case "map[string]object":
+			checks = append(checks, fmt.Sprintf("\tfor key, item := range %s {\n\t\tif err := item.Validate(); err != nil {\n\t\t\treturn fmt.Errorf(\"%s[%%s]: %%w\", key, err)\n\t\t}\n\t}", expr, field.Name))
+		}
+	}
+
+	checks = append(checks, "\treturn nil")
+
+	return fmt.Sprintf("\n// Validate 校验必填参数\nfunc (req %s) Validate() error {\n%s\n}\n", name, strings.Join(checks, "\n\n"))
+}
+
+// zeroCheck 判断 必填字段 为空 的表达式
+func zeroCheck(typ string, expr string) string {
+	switch {
+	case typ == "string":
+		return expr + ` == ""`
+	case typ == "int" || typ == "int64" || typ == "float64":
+		return expr + " == 0"
+	case strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map["):
+		return "len(" + expr + ") == 0"
+	}
+	return ""
+}
+
+// nonZeroCheck 判断 字段 不为空 的表达式
+func nonZeroCheck(typ string, expr string) string {
+	switch {
+	case typ == "bool":
+		return expr
+	case typ == "string":
+		return expr + ` != ""`
+	case typ == "int" || typ == "int64" || typ == "float64":
+		return expr + " != 0"
+	case strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map["):
+		return "len(" + expr + ") != 0"
+	}
+	return ""
+}
+
+// paramsDef 生成 Params 方法，将结构体 转为 query 参数
+func paramsDef(name string, params []Param) string {
+	var lines []string
+	for _, param := range params {
+		expr := "req." + fieldName(param.Name)
+		value := expr
+		if param.Type != "string" {
+			value = "fmt.Sprint(" + expr + ")"
+		}
+		add := fmt.Sprintf("params.Add(\"%s\", %s)", param.Name, value)
+
+		nonZero := nonZeroCheck(param.Type, expr)
+		if param.Required || nonZero == "" {
+			lines = append(lines, "\t"+add)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("\tif %s {\n\t\t%s\n\t}", nonZero, add))
+	}
+
+	return fmt.Sprintf("\n// Params query 参数\nfunc (req %s) Params() url.Values {\n\tparams := url.Values{}\n%s\n\treturn params\n}\n", name, strings.Join(lines, "\n"))
+}
+
+// typedFuncDef 生成 类型化的调用方法
+func typedFuncDef(api Api) string {
+	name := funcName(api)
+	isGet := strings.Contains(api.Request, "GET http")
+
+	respType := "[]byte"
+	if len(api.ResponseFields) > 0 {
+		respType = name + "Response"
+	}
+
+	var body []string
+	body = append(body, "\tif err = req.Validate(); err != nil {\n\t\treturn\n\t}")
+
+	args := []string{"ctx"}
+	params := requestParams(api)
+	if len(params) > 0 {
+		body = append(body, "\tparams := req.Params()")
+	}
+
+	if api.Auth == "query" || (api.Auth == "body" && !isGet) {
+		body = append(body, "\tvar accessToken string\n\taccessToken, err = ctx.GetAccessTokenHandler(ctx)\n\tif err != nil {\n\t\treturn\n\t}")
+	}
+	if api.Auth == "query" {
+		if len(params) == 0 {
+			body = append(body, "\tparams := url.Values{}")
+		}
+		body = append(body, "\tparams.Set(\"access_token\", accessToken)")
+	}
+
+	if !isGet {
+		if api.Auth == "body" {
+			body = append(body, fmt.Sprintf("\tpayload, err := json.Marshal(struct {\n\t\tAccessToken string `json:\"access_token\"`\n\t\t%sRequest\n\t}{accessToken, req})\n\tif err != nil {\n\t\treturn\n\t}", name))
+		} else {
+			body = append(body, "\tpayload, err := json.Marshal(req)\n\tif err != nil {\n\t\treturn\n\t}")
+		}
+		if api.Sign == "session_key" {
+			body = append(body, "\tparams.Set(\"signature\", microapp.SessionSignature(sessionKey, payload))\n\tparams.Set(\"sig_method\", \"hmac_sha256\")")
+		}
+		args = append(args, "payload")
+	}
+
+	if len(api.GetParams) > 0 || api.Auth == "query" {
+		args = append(args, "params")
+	}
+
+	call := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if len(api.ResponseFields) > 0 {
+		body = append(body, fmt.Sprintf("\traw, err := %s\n\tif err != nil {\n\t\treturn\n\t}\n\n\terr = json.Unmarshal(raw, &resp)\n\treturn", call))
+	} else {
+		body = append(body, fmt.Sprintf("\treturn %s", call))
+	}
+
+	_AUTH_NOTE_ := ""
+	switch api.Auth {
+	case "query", "body", "header":
+		_AUTH_NOTE_ = "，自动填充 access_token"
+	case "secret":
+		_AUTH_NOTE_ = "，自动填充 appid/secret"
+	}
+
+	_SIGN_ARG_ := ""
+	if api.Sign == "session_key" {
+		_AUTH_NOTE_ += "，使用 sessionKey 计算 用户登录态签名"
+		_SIGN_ARG_ = "sessionKey string, "
+	}
+
+	tpl := typedFuncTpl
+	tpl = strings.ReplaceAll(tpl, "_AUTH_NOTE_", _AUTH_NOTE_)
+	tpl = strings.ReplaceAll(tpl, "_SIGN_ARG_", _SIGN_ARG_)
+	tpl = strings.ReplaceAll(tpl, "_TITLE_", api.Name)
+	tpl = strings.ReplaceAll(tpl, "_SEE_", api.See)
+	tpl = strings.ReplaceAll(tpl, "_REQUEST_", api.Request)
+	tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", name)
+	tpl = strings.ReplaceAll(tpl, "_RESP_TYPE_", respType)
+	tpl = strings.ReplaceAll(tpl, "_BODY_", strings.Join(body, "\n\n"))
+	return tpl
+}
+
+// buildTyped 生成 分组的 类型化代码
+func buildTyped(group ApiGroup) (code string, ok bool) {
+	builder := newTypedBuilder()
+
+	var funcs []string
+	for _, api := range group.Apis {
+		if !typedApi(api) {
+			continue
+		}
+		name := funcName(api)
+
+		params := requestParams(api)
+		builder.structDef(name+"Request", api.Name+" 请求参数", params, api.BodyFields, true)
+		builder.structs = append(builder.structs, validateDef(name+"Request", params, api.BodyFields))
+		if len(params) > 0 {
+			builder.structs = append(builder.structs, paramsDef(name+"Request", params))
+		}
+		if len(api.ResponseFields) > 0 {
+			builder.structDef(name+"Response", api.Name+" 响应", nil, api.ResponseFields, false)
+		}
+
+		funcs = append(funcs, typedFuncDef(api))
+	}
+
+	if len(funcs) == 0 {
+		return "", false
+	}
+
+	pkg := path.Base(group.Package)
+	code = "package " + pkg + "\n" + strings.Join(builder.structs, "") + strings.Join(funcs, "")
+	return code, true
+}
+
+/*
+responseAssert 测试用例中 断言 响应 按 模拟响应 解码
+
+优先选择 顶层的 字符串字段（模拟值 为 大写的字段名），其次为 错误信息（模拟值 为 ok）；
+未定义响应结构时 断言 原样返回 模拟响应
+*/
+func responseAssert(api Api) string {
+	if len(api.ResponseFields) == 0 {
+		want := strconv.Quote(mockResponse(api))
+		return fmt.Sprintf("\n\t\t\tif !tt.wantErr && string(resp) != %s {\n\t\t\t\tt.Errorf(\"resp = %%s, want %%s\", resp, %s)\n\t\t\t}", want, want)
+	}
+
+	var picked *Field
+	for i, field := range api.ResponseFields {
+		if field.Type != "string" {
+			continue
+		}
+		if field.Name != "errmsg" && field.Name != "err_tips" {
+			picked = &api.ResponseFields[i]
+			break
+		}
+		if picked == nil {
+			picked = &api.ResponseFields[i]
+		}
+	}
+	if picked == nil {
+		return ""
+	}
+
+	name, want := fieldName(picked.Name), sampleJSONValue(*picked)
+	return fmt.Sprintf("\n\t\t\tif !tt.wantErr && resp.%s != %s {\n\t\t\t\tt.Errorf(\"resp.%s = %%v, want %%v\", resp.%s, %s)\n\t\t\t}", name, want, name, name, want)
+}
+
+// probe 测试用例中 断言 按示例值 发送的 请求字段
+type probe struct {
+	field Field  // 顶层字段，非必填时 测试用例 额外填充
+	path  string // 请求体中的路径，如 tasks.0.content；为空时 断言 query 参数
+}
+
+/*
+requestProbe 选择 测试用例中 断言的 请求字段
+
+依次选择 第一个必填的 请求体字段（包括 必填结构体中的 必填字段）、第一个必填的 query 参数、
+第一个可选的 请求体字段、第一个可选的 query 参数
+*/
+func requestProbe(api Api) (p probe, ok bool) {
+	body := !strings.Contains(api.Request, "GET http")
+	if body {
+		for _, field := range api.BodyFields {
+			if path, found := requiredPath(field); found {
+				return probe{field: field, path: path}, true
+			}
+		}
+	}
+
+	params := paramFields(requestParams(api))
+	for _, field := range params {
+		if field.Required && probeType(field.Type) {
+			return probe{field: field}, true
+		}
+	}
+
+	if body {
+		for _, field := range api.BodyFields {
+			if !field.Required && probeType(field.Type) {
+				p = probe{field: field, path: field.Name}
+				if field.Type == "[]string" {
+					p.path += ".0"
+				}
+				return p, true
+			}
+		}
+	}
+
+	for _, field := range params {
+		if !field.Required && probeType(field.Type) {
+			return probe{field: field}, true
+		}
+	}
+	return
+}
+
+// requiredPath 必填字段 中 第一个 示例值 为标量 的路径
+func requiredPath(field Field) (path string, ok bool) {
+	if !field.Required || field.AllowEmpty {
+		return
+	}
+	switch field.Type {
+	case "[]string":
+		return field.Name + ".0", true
+	case "object", "[]object":
+		prefix := field.Name + "."
+		if field.Type == "[]object" {
+			prefix += "0."
+		}
+		for _, nested := range field.Fields {
+			if path, ok = requiredPath(nested); ok {
+				return prefix + path, true
+			}
+		}
+		return
+	}
+	return field.Name, probeType(field.Type)
+}
+
+func probeType(typ string) bool {
+	switch typ {
+	case "string", "int", "int64", "float64", "bool", "[]string":
+		return true
+	}
+	return false
+}
+
+// requestAssert 测试用例中 断言 请求字段 按示例值 发送
+func requestAssert(api Api, p probe) string {
+	name := funcName(api)
+
+	typ := p.field.Type
+	if p.path != p.field.Name && typ != "[]string" {
+		// 嵌套字段 的示例值 均为 "test" 或 1，按叶子字段 确定类型
+		typ = leafType(p.field, strings.Split(p.path, ".")[1:])
+	}
+	if typ == "[]string" {
+		typ = "string"
+	}
+
+	if p.path == "" {
+		return fmt.Sprintf("\n\tenv.Capture.AssertQuery(t, api%s, %q, %s)", name, p.field.Name, sampleQueryValue(typ, false))
+	}
+	return fmt.Sprintf("\n\tenv.Capture.AssertJSONField(t, api%s, %q, %s)", name, p.path, sampleQueryValue(typ, true))
+}
+
+// leafType 按路径 查找 嵌套字段的类型
+func leafType(field Field, path []string) string {
+	for _, key := range path {
+		for _, nested := range field.Fields {
+			if nested.Name == key {
+				field = nested
+				break
+			}
+		}
+	}
+	return field.Type
+}
+
+// sampleQueryValue 示例值 在请求中的形式，json 为 true 时 为 JSON 值，否则 为 query 参数字符串
+func sampleQueryValue(typ string, json bool) string {
+	value := map[string]string{"string": "test", "int": "1", "int64": "1", "float64": "1", "bool": "true"}[typ]
+	if json && typ != "string" {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// signValue 测试用例 和 示例中 传入的 session_key
+func signValue(api Api) string {
+	if api.Sign == "session_key" {
+		return `"SESSION_KEY", `
+	}
+	return ""
+}
+
+// sampleValue 测试用例中 必填字段的示例值
+func (builder *typedBuilder) sampleValue(parent string, field Field) string {
+	switch field.Type {
+	case "string":
+		return `"test"`
+	case "int", "int64", "float64":
+		return "1"
+	case "bool":
+		return "true"
+	case "[]string":
+		return `[]string{"test"}`
+	case "map[string]string":
+		return `map[string]string{"test": "test"}`
+	case "object":
+		literal := nestedName(parent, field) + "{" + builder.sampleFields(nestedName(parent, field), nil, field.Fields) + "}"
+		if !field.Required {
+			return "&" + literal
+		}
+		return literal
+	case "[]object":
+		return "[]" + nestedName(parent, field) + "{{" + builder.sampleFields(nestedName(parent, field), nil, field.Fields) + "}}"
+	case "map[string]object":
+		return "map[string]" + nestedName(parent, field) + `{"test": {` + builder.sampleFields(nestedName(parent, field), nil, field.Fields) + "}}"
+	}
+	return ""
+}
+
+// sampleFields 填充 必填字段 的结构体字面量
+func (builder *typedBuilder) sampleFields(name string, params []Param, fields []Field) string {
+	var values []string
+	for _, param := range params {
+		if param.Required {
+			values = append(values, fieldName(param.Name)+": "+builder.sampleValue(name, Field{Name: param.Name, Type: param.Type}))
+		}
+	}
+	for _, field := range fields {
+		if field.Required {
+			values = append(values, fieldName(field.Name)+": "+builder.sampleValue(name, field))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// buildTypedTest 生成 类型化代码 的测试
+func buildTypedTest(group ApiGroup) (code string, ok bool) {
+	builder := newTypedBuilder()
+
+	var funcs []string
+	for _, api := range group.Apis {
+		if !typedApi(api) {
+			continue
+		}
+		name := funcName(api)
+
+		params := requestParams(api)
+		sample := builder.sampleFields(name+"Request", params, api.BodyFields)
+		p, probed := requestProbe(api)
+		if probed && !p.field.Required {
+			if sample != "" {
+				sample += ", "
+			}
+			sample += fieldName(p.field.Name) + ": " + builder.sampleValue(name+"Request", p.field)
+		}
+		_CASES_ := fmt.Sprintf(`{name: "case1", req: %sRequest{%s}, wantErr: false},`, name, sample)
+		if needValidate(api.BodyFields) || needValidate(paramFields(params)) {
+			_CASES_ += fmt.Sprintf("\n\t\t{name: \"required\", req: %sRequest{}, wantErr: true},", name)
+		}
+
+		_ASSERT_ := ""
+		if api.Auth != "" && api.Auth != "secret" {
+			_ASSERT_ = fmt.Sprintf("\n\tenv.Capture.AssertAccessToken(t, api%s, env.MicroApp)", name)
+		}
+		if probed {
+			_ASSERT_ += requestAssert(api, p)
+		}
+
+		_RESP_VAR_, _RESP_ASSERT_ := "_", responseAssert(api)
+		if _RESP_ASSERT_ != "" {
+			_RESP_VAR_ = "resp"
+		}
+
+		tpl := strings.ReplaceAll(typedTestFuncTpl, "_FUNC_NAME_", name)
+		tpl = strings.ReplaceAll(tpl, "_CASES_", _CASES_)
+		tpl = strings.ReplaceAll(tpl, "_RESP_VAR_", _RESP_VAR_)
+		tpl = strings.ReplaceAll(tpl, "_RESP_ASSERT_", _RESP_ASSERT_)
+		tpl = strings.ReplaceAll(tpl, "_ASSERT_", _ASSERT_)
+		tpl = strings.ReplaceAll(tpl, "_SIGN_VALUE_", signValue(api))
+		funcs = append(funcs, tpl)
+	}
+
+	if len(funcs) == 0 {
+		return "", false
+	}
+
+	pkg := path.Base(group.Package)
+	code = "package " + pkg + "\n" + strings.Join(funcs, "")
+	return code, true
+}
+
+// buildTypedExample 生成 类型化代码 的示例
+func buildTypedExample(group ApiGroup) (code string, ok bool) {
+	pkg := path.Base(group.Package)
+
+	var funcs []string
+	for _, api := range group.Apis {
+		if !typedApi(api) {
+			continue
+		}
+
+		tpl := strings.ReplaceAll(typedExampleFuncTpl, "_FUNC_NAME_", funcName(api))
+		tpl = strings.ReplaceAll(tpl, "_PACKAGE_", pkg)
+		tpl = strings.ReplaceAll(tpl, "_SIGN_VALUE_", signValue(api))
+		funcs = append(funcs, tpl)
+	}
+
+	if len(funcs) == 0 {
+		return "", false
+	}
+
+	code = "package " + pkg + "_test\n" + strings.Join(funcs, "")
+	return code, true
+}
+
+func paramFields(params []Param) (fields []Field) {
+	for _, param := range params {
+		fields = append(fields, Field{Name: param.Name, Type: param.Type, Required: param.Required})
+	}
+	return
+}
+
+var typedFuncTpl = `
+/*
+_TITLE_
+
+使用结构体 作为请求参数和响应 的 _FUNC_NAME_，调用前校验必填参数_AUTH_NOTE_
+
+See: _SEE_
+
+_REQUEST_
+*/
+func _FUNC_NAME_Typed(ctx *microapp.MicroApp, _SIGN_ARG_req _FUNC_NAME_Request) (resp _RESP_TYPE_, err error) {
+_BODY_
+}
+`
+
+var typedTestFuncTpl = `
+func Test_FUNC_NAME_Typed(t *testing.T) {
+	env := test.NewEnv(t, microapp.Config{})
+
+	tests := []struct {
+		name    string
+		req     _FUNC_NAME_Request
+		wantErr bool
+	}{
+		_CASES_
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_RESP_VAR_, err := _FUNC_NAME_Typed(env.MicroApp, _SIGN_VALUE_tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("_FUNC_NAME_Typed() error = %v, wantErr %v", err, tt.wantErr)
+			}_RESP_ASSERT_
+		})
+	}
+_ASSERT_
+}
+`
+
+var typedExampleFuncTpl = `
+func Example_FUNC_NAME_Typed() {
+	var ctx *microapp.MicroApp
+
+	req := _PACKAGE_._FUNC_NAME_Request{}
+	resp, err := _PACKAGE_._FUNC_NAME_Typed(ctx, _SIGN_VALUE_req)
+
+	fmt.Println(resp, err)
+}
+`
@@ -0,0 +1,380 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package component 第三方平台（服务商）代小程序 开发和管理
+
+ 1. 平台 每 10 分钟 推送 component_ticket，TicketHandler 校验、解密 后 缓存
+ 2. 使用 component_ticket 换取 component_access_token，见 ComponentAccessToken
+ 3. 获取 预授权码 PreAuthCode，引导 小程序管理员 在 AuthorizationURL 授权
+ 4. 授权后 使用 授权码 换取 authorizer_access_token 和 authorizer_refresh_token，见 ExchangeAuthorizationCode
+ 5. NewMicroApp 创建 代小程序调用接口 的实例，authorizer_access_token 过期后 使用 authorizer_refresh_token 自动刷新
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/thirdparty/API/smallprogram/authdevelopment/componentAccessToken
+*/
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	"github.com/faabiosr/cachego/file"
+	"github.com/fastwego/microapp"
+)
+
+var ServerUrl = "https://open.microapp.bytedance.com" // 第三方平台 api 服务器地址
+
+const (
+	apiComponentAccessToken = "/openapi/v1/auth/tp/token"
+	apiPreAuthCode          = "/openapi/v2/auth/pre_auth_code"
+	apiAuthorizerToken      = "/openapi/v1/oauth/token"
+	authorizationPage       = "/mappconsole/tp/authorization"
+)
+
+/*
+第三方平台配置
+*/
+type Config struct {
+	ComponentAppId     string
+	ComponentAppSecret string
+	Token              string // 消息推送 Token，用于 校验 component_ticket 推送的签名，为空时 拒绝推送
+	EncodingAESKey     string // 消息加解密 Key，用于 解密 component_ticket 推送
+}
+
+/*
+Component 第三方平台 实例
+*/
+type Component struct {
+	Config     Config
+	Logger     *log.Logger
+	Cache      cachego.Cache // 缓存 component_ticket 和 各类 token，多个进程 共享时 需要使用 共享的缓存
+	HTTPClient *http.Client  // 为空时使用 http.DefaultClient
+	ServerUrl  string        // 为空时使用 全局 ServerUrl
+
+	// 防止多个 goroutine 并发刷新冲突，刷新 authorizer_access_token 时 可能需要刷新 component_access_token，因此 分开加锁
+	componentLock  sync.Mutex
+	authorizerLock sync.Mutex
+}
+
+/*
+创建 第三方平台 实例
+*/
+func New(config Config) (component *Component) {
+	component = &Component{
+		Config: config,
+		Cache:  file.New(os.TempDir()),
+		Logger: log.New(os.Stdout, "[fastwego/microapp/component] ", log.LstdFlags|log.Llongfile),
+	}
+	return
+}
+
+/*
+Authorization 授权信息
+*/
+type Authorization struct {
+	AuthorizerAppId        string `json:"authorizer_appid"`         // 授权小程序 appid
+	AuthorizerAccessToken  string `json:"authorizer_access_token"`  // 授权小程序 接口调用凭据
+	ExpiresIn              int    `json:"expires_in"`               // authorizer_access_token 有效期，单位 秒
+	AuthorizerRefreshToken string `json:"authorizer_refresh_token"` // 刷新令牌，用于 刷新 authorizer_access_token
+	RefreshExpiresIn       int    `json:"refresh_expires_in"`       // authorizer_refresh_token 有效期，单位 秒
+	AuthorizePermission    []struct {
+		Id          int    `json:"id"`
+		Category    string `json:"category"`
+		Description string `json:"description"`
+	} `json:"authorize_permission"` // 授权给 第三方平台 的权限集
+}
+
+/*
+ComponentAccessToken 获取 component_access_token
+
+缓存中没有 或 已过期 时 使用 component_ticket 刷新，过期时间 设置为 0.9 * expires_in
+*/
+func (component *Component) ComponentAccessToken() (accessToken string, err error) {
+	key := component.cacheKey("component_access_token")
+	if accessToken, _ = component.Cache.Fetch(key); accessToken != "" {
+		return
+	}
+
+	component.componentLock.Lock()
+	defer component.componentLock.Unlock()
+
+	if accessToken, _ = component.Cache.Fetch(key); accessToken != "" {
+		return
+	}
+
+	ticket, err := component.Ticket()
+	if err != nil {
+		return
+	}
+
+	params := url.Values{}
+	params.Add("component_appid", component.Config.ComponentAppId)
+	params.Add("component_appsecret", component.Config.ComponentAppSecret)
+	params.Add("component_ticket", ticket)
+
+	result := struct {
+		ComponentAccessToken string `json:"component_access_token"`
+		ExpiresIn            int    `json:"expires_in"`
+	}{}
+	if err = component.do(http.MethodGet, apiComponentAccessToken+"?"+params.Encode(), &result); err != nil {
+		return
+	}
+	if result.ComponentAccessToken == "" {
+		return "", fmt.Errorf("%s: no component_access_token", apiComponentAccessToken)
+	}
+
+	_ = component.Cache.Save(key, result.ComponentAccessToken, expires(result.ExpiresIn))
+	component.logf("refresh component_access_token %s %d", result.ComponentAccessToken, result.ExpiresIn)
+
+	return result.ComponentAccessToken, nil
+}
+
+/*
+PreAuthCode 获取 预授权码，用于 生成 授权链接，有效期 10 分钟
+*/
+func (component *Component) PreAuthCode() (preAuthCode string, err error) {
+	params, err := component.componentParams()
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		PreAuthCode string `json:"pre_auth_code"`
+		ExpiresIn   int    `json:"expires_in"`
+	}{}
+	if err = component.do(http.MethodPost, apiPreAuthCode+"?"+params.Encode(), &result); err != nil {
+		return
+	}
+	if result.PreAuthCode == "" {
+		return "", fmt.Errorf("%s: no pre_auth_code", apiPreAuthCode)
+	}
+	return result.PreAuthCode, nil
+}
+
+/*
+AuthorizationURL 授权链接，小程序管理员 打开后 授权给 第三方平台，授权完成 跳转到 redirectUri 并附带 authorization_code
+*/
+func (component *Component) AuthorizationURL(preAuthCode string, redirectUri string) string {
+	params := url.Values{}
+	params.Add("component_appid", component.Config.ComponentAppId)
+	params.Add("pre_auth_code", preAuthCode)
+	params.Add("redirect_uri", redirectUri)
+	return component.serverUrl() + authorizationPage + "?" + params.Encode()
+}
+
+/*
+ExchangeAuthorizationCode 使用 授权码 换取 授权信息，缓存 authorizer_access_token 和 authorizer_refresh_token
+
+authorizer_refresh_token 需要 开发者 持久化保存，缓存丢失时 使用 SetAuthorizerRefreshToken 恢复
+*/
+func (component *Component) ExchangeAuthorizationCode(authorizationCode string) (auth Authorization, err error) {
+	params, err := component.componentParams()
+	if err != nil {
+		return
+	}
+	params.Add("authorization_code", authorizationCode)
+	params.Add("grant_type", "app_to_tp_authorization_code")
+
+	if err = component.do(http.MethodGet, apiAuthorizerToken+"?"+params.Encode(), &auth); err != nil {
+		return
+	}
+	err = component.saveAuthorization(auth)
+	return
+}
+
+/*
+AuthorizerAccessToken 获取 授权小程序的 authorizer_access_token
+
+缓存中没有 或 已过期 时 使用 authorizer_refresh_token 刷新
+*/
+func (component *Component) AuthorizerAccessToken(authorizerAppId string) (accessToken string, err error) {
+	key := component.cacheKey("authorizer_access_token", authorizerAppId)
+	if accessToken, _ = component.Cache.Fetch(key); accessToken != "" {
+		return
+	}
+
+	component.authorizerLock.Lock()
+	defer component.authorizerLock.Unlock()
+
+	if accessToken, _ = component.Cache.Fetch(key); accessToken != "" {
+		return
+	}
+
+	auth, err := component.RefreshAuthorizerAccessToken(authorizerAppId)
+	return auth.AuthorizerAccessToken, err
+}
+
+/*
+RefreshAuthorizerAccessToken 使用 authorizer_refresh_token 刷新 authorizer_access_token
+
+刷新后 authorizer_refresh_token 也会更新
+*/
+func (component *Component) RefreshAuthorizerAccessToken(authorizerAppId string) (auth Authorization, err error) {
+	refreshToken, _ := component.Cache.Fetch(component.cacheKey("authorizer_refresh_token", authorizerAppId))
+	if refreshToken == "" {
+		return auth, fmt.Errorf("no authorizer_refresh_token for %s", authorizerAppId)
+	}
+
+	params, err := component.componentParams()
+	if err != nil {
+		return
+	}
+	params.Add("authorizer_refresh_token", refreshToken)
+	params.Add("grant_type", "app_to_tp_refresh_token")
+
+	if err = component.do(http.MethodGet, apiAuthorizerToken+"?"+params.Encode(), &auth); err != nil {
+		return
+	}
+	if auth.AuthorizerAppId == "" {
+		auth.AuthorizerAppId = authorizerAppId
+	}
+	err = component.saveAuthorization(auth)
+	return
+}
+
+// SetAuthorizerRefreshToken 恢复 持久化保存的 authorizer_refresh_token
+func (component *Component) SetAuthorizerRefreshToken(authorizerAppId string, refreshToken string) error {
+	return component.Cache.Save(component.cacheKey("authorizer_refresh_token", authorizerAppId), refreshToken, 0)
+}
+
+/*
+NewMicroApp 创建 代授权小程序调用接口 的实例
+
+实例的 GetAccessTokenHandler 返回 authorizer_access_token，NoticeAccessTokenExpireHandler 删除缓存 以便下次 使用 authorizer_refresh_token 刷新；
+实例的 接口请求 发往 第三方平台 api 服务器，用于 code_management 等 第三方平台 代小程序 调用的接口
+*/
+func (component *Component) NewMicroApp(authorizerAppId string) (ctx *microapp.MicroApp) {
+	ctx = microapp.New(microapp.Config{AppId: authorizerAppId, ComponentAppId: component.Config.ComponentAppId})
+	ctx.Cache = component.Cache
+	ctx.Logger = component.Logger
+	ctx.Client.HTTPClient = component.HTTPClient
+	ctx.Client.ServerUrl = component.serverUrl()
+
+	ctx.GetAccessTokenHandler = func(ctx *microapp.MicroApp) (accessToken string, err error) {
+		return component.AuthorizerAccessToken(ctx.Config.AppId)
+	}
+	ctx.NoticeAccessTokenExpireHandler = func(ctx *microapp.MicroApp) (err error) {
+		component.logf("authorizer_access_token of %s expired", ctx.Config.AppId)
+		return component.Cache.Delete(component.cacheKey("authorizer_access_token", ctx.Config.AppId))
+	}
+	return
+}
+
+func (component *Component) saveAuthorization(auth Authorization) (err error) {
+	if auth.AuthorizerAccessToken == "" || auth.AuthorizerAppId == "" {
+		return fmt.Errorf("%s: no authorizer_access_token", apiAuthorizerToken)
+	}
+
+	err = component.Cache.Save(component.cacheKey("authorizer_access_token", auth.AuthorizerAppId), auth.AuthorizerAccessToken, expires(auth.ExpiresIn))
+	if err != nil {
+		return
+	}
+	if auth.AuthorizerRefreshToken != "" {
+		err = component.Cache.Save(component.cacheKey("authorizer_refresh_token", auth.AuthorizerAppId), auth.AuthorizerRefreshToken, expires(auth.RefreshExpiresIn))
+	}
+	component.logf("refresh authorizer_access_token %s %s %d", auth.AuthorizerAppId, auth.AuthorizerAccessToken, auth.ExpiresIn)
+	return
+}
+
+// componentParams component_appid 和 component_access_token 参数
+func (component *Component) componentParams() (params url.Values, err error) {
+	accessToken, err := component.ComponentAccessToken()
+	if err != nil {
+		return
+	}
+
+	params = url.Values{}
+	params.Add("component_appid", component.Config.ComponentAppId)
+	params.Add("component_access_token", accessToken)
+	return
+}
+
+/*
+do 发送请求 并解析响应
+
+响应 没有 errno 字段 或 errno 为 0 时 解析到 result，否则 返回错误
+*/
+func (component *Component) do(method string, uri string, result interface{}) (err error) {
+	req, err := http.NewRequest(method, component.serverUrl()+uri, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Add("User-Agent", microapp.UserAgent)
+
+	client := component.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s RETURN %s", method, req.URL.Path, response.Status)
+	}
+
+	resp, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	errorResponse := struct {
+		Errno   int64  `json:"errno"`
+		Message string `json:"message"`
+	}{}
+	if err = json.Unmarshal(resp, &errorResponse); err != nil {
+		return fmt.Errorf("Unmarshal error %s", string(resp))
+	}
+	if errorResponse.Errno != 0 {
+		return fmt.Errorf("%s", string(resp))
+	}
+
+	return json.Unmarshal(resp, result)
+}
+
+func (component *Component) serverUrl() string {
+	if component.ServerUrl != "" {
+		return component.ServerUrl
+	}
+	return ServerUrl
+}
+
+func (component *Component) cacheKey(kind string, appid ...string) string {
+	key := kind + ":" + component.Config.ComponentAppId
+	for _, id := range appid {
+		key += ":" + id
+	}
+	return key
+}
+
+func (component *Component) logf(format string, v ...interface{}) {
+	if component.Logger != nil {
+		component.Logger.Printf(format, v...)
+	}
+}
+
+// expires 缓存有效期 设置为 0.9 * expiresIn 提供一定冗余，expiresIn 为 0 时 不过期
+func expires(expiresIn int) time.Duration {
+	return time.Duration(expiresIn) * time.Second * 9 / 10
+}
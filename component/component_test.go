@@ -0,0 +1,159 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/faabiosr/cachego/sync"
+)
+
+func newTestComponent(config Config, serverUrl string) *Component {
+	component := New(config)
+	component.Cache = sync.New()
+	component.Logger = nil
+	component.ServerUrl = serverUrl
+	return component
+}
+
+// platform 模拟 第三方平台 token 接口
+type platform struct {
+	componentTokens  int32
+	authorizerTokens int32
+}
+
+func (p *platform) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiComponentAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("component_ticket") != "TICKET" || q.Get("component_appsecret") != "COMPONENT_SECRET" {
+			_, _ = w.Write([]byte(`{"errno":40001,"message":"invalid ticket"}`))
+			return
+		}
+		n := atomic.AddInt32(&p.componentTokens, 1)
+		_, _ = fmt.Fprintf(w, `{"component_access_token":"COMPONENT_TOKEN_%d","expires_in":7200}`, n)
+	})
+	mux.HandleFunc(apiPreAuthCode, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Query().Get("component_access_token") != "COMPONENT_TOKEN_1" {
+			t.Errorf("pre_auth_code request = %s %s", r.Method, r.URL)
+		}
+		_, _ = w.Write([]byte(`{"pre_auth_code":"PRE_AUTH_CODE","expires_in":600}`))
+	})
+	mux.HandleFunc(apiAuthorizerToken, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("grant_type") == "app_to_tp_authorization_code" && q.Get("authorization_code") == "AUTH_CODE":
+		case q.Get("grant_type") == "app_to_tp_refresh_token" && q.Get("authorizer_refresh_token") != "":
+		default:
+			_, _ = w.Write([]byte(`{"errno":40004,"message":"invalid grant"}`))
+			return
+		}
+		n := atomic.AddInt32(&p.authorizerTokens, 1)
+		_, _ = fmt.Fprintf(w, `{"authorizer_appid":"APPID","authorizer_access_token":"AUTHORIZER_TOKEN_%d","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN_%d","refresh_expires_in":2592000}`, n, n)
+	})
+	return mux
+}
+
+func TestComponentAccessToken(t *testing.T) {
+	p := &platform{}
+	s := httptest.NewServer(p.handler(t))
+	defer s.Close()
+
+	component := newTestComponent(Config{ComponentAppId: "COMPONENT_APPID", ComponentAppSecret: "COMPONENT_SECRET"}, s.URL)
+
+	if _, err := component.ComponentAccessToken(); err != ErrorNoTicket {
+		t.Fatalf("without ticket: err = %v", err)
+	}
+
+	_ = component.SetTicket("TICKET")
+	for i := 0; i < 2; i++ {
+		accessToken, err := component.ComponentAccessToken()
+		if err != nil || accessToken != "COMPONENT_TOKEN_1" {
+			t.Fatalf("ComponentAccessToken() = %s, %v", accessToken, err)
+		}
+	}
+	if p.componentTokens != 1 {
+		t.Errorf("component_access_token refreshed %d times, want cached", p.componentTokens)
+	}
+
+	preAuthCode, err := component.PreAuthCode()
+	if err != nil || preAuthCode != "PRE_AUTH_CODE" {
+		t.Fatalf("PreAuthCode() = %s, %v", preAuthCode, err)
+	}
+
+	link, _ := url.Parse(component.AuthorizationURL(preAuthCode, "https://example.com/callback"))
+	if link.Path != authorizationPage || link.Query().Get("pre_auth_code") != "PRE_AUTH_CODE" || link.Query().Get("redirect_uri") != "https://example.com/callback" {
+		t.Errorf("AuthorizationURL = %s", link)
+	}
+
+	_ = component.SetTicket("EXPIRED_TICKET")
+	_ = component.Cache.Delete(component.cacheKey("component_access_token"))
+	if _, err = component.ComponentAccessToken(); err == nil {
+		t.Error("errno should be returned as error")
+	}
+}
+
+func TestAuthorizerAccessToken(t *testing.T) {
+	p := &platform{}
+	s := httptest.NewServer(p.handler(t))
+	defer s.Close()
+
+	component := newTestComponent(Config{ComponentAppId: "COMPONENT_APPID", ComponentAppSecret: "COMPONENT_SECRET"}, s.URL)
+	_ = component.SetTicket("TICKET")
+
+	if _, err := component.AuthorizerAccessToken("APPID"); err == nil {
+		t.Fatal("should fail before authorization")
+	}
+
+	auth, err := component.ExchangeAuthorizationCode("AUTH_CODE")
+	if err != nil || auth.AuthorizerAccessToken != "AUTHORIZER_TOKEN_1" || auth.AuthorizerRefreshToken != "REFRESH_TOKEN_1" {
+		t.Fatalf("ExchangeAuthorizationCode() = %+v, %v", auth, err)
+	}
+
+	ctx := component.NewMicroApp("APPID")
+	accessToken, err := ctx.GetAccessTokenHandler(ctx)
+	if err != nil || accessToken != "AUTHORIZER_TOKEN_1" {
+		t.Fatalf("GetAccessTokenHandler() = %s, %v", accessToken, err)
+	}
+
+	// 过期通知 后 使用 authorizer_refresh_token 刷新
+	if err = ctx.NoticeAccessTokenExpireHandler(ctx); err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _ = ctx.GetAccessTokenHandler(ctx)
+	if accessToken != "AUTHORIZER_TOKEN_2" || p.authorizerTokens != 2 {
+		t.Errorf("after expire: token = %s, refreshed %d times", accessToken, p.authorizerTokens)
+	}
+	if refreshToken, _ := component.Cache.Fetch(component.cacheKey("authorizer_refresh_token", "APPID")); refreshToken != "REFRESH_TOKEN_2" {
+		t.Errorf("authorizer_refresh_token = %s, want rotated", refreshToken)
+	}
+
+	if ctx.Config.ComponentAppId != "COMPONENT_APPID" || ctx.Client.ServerUrl != s.URL {
+		t.Errorf("config = %+v, ServerUrl = %s, want component", ctx.Config, ctx.Client.ServerUrl)
+	}
+
+	// 缓存丢失后 使用 持久化的 authorizer_refresh_token 恢复
+	restored := newTestComponent(component.Config, s.URL)
+	_ = restored.SetTicket("TICKET")
+	_ = restored.SetAuthorizerRefreshToken("APPID", "REFRESH_TOKEN_2")
+	if accessToken, err = restored.AuthorizerAccessToken("APPID"); err != nil || accessToken != "AUTHORIZER_TOKEN_3" {
+		t.Errorf("restored AuthorizerAccessToken() = %s, %v", accessToken, err)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/fastwego/microapp/server"
+)
+
+var (
+	ErrorNoTicket    = errors.New("component_ticket not received yet")
+	ErrorDecrypt     = errors.New("decrypt message failed")
+	ErrorSignature   = server.ErrorSignature
+	ErrorNoToken     = server.ErrorNoToken
+	ErrorComponentId = errors.New("message is not for this component")
+)
+
+/*
+EncryptedMessage 第三方平台 推送的加密消息
+
+	{"Nonce": "...", "TimeStamp": "...", "Encrypt": "...", "MsgSignature": "..."}
+
+MsgSignature = sha1(将 Token TimeStamp Nonce Encrypt 按字典序排序后 拼接)
+*/
+type EncryptedMessage struct {
+	Nonce        server.String `json:"Nonce"`
+	TimeStamp    server.String `json:"TimeStamp"`
+	Encrypt      string        `json:"Encrypt"`
+	MsgSignature string        `json:"MsgSignature"`
+}
+
+// TicketMessage 解密后的 component_ticket 推送
+type TicketMessage struct {
+	Ticket       string `json:"Ticket"`
+	CreateTime   int64  `json:"CreateTime"`
+	MsgType      string `json:"MsgType"`
+	FromUserName string `json:"FromUserName"`
+	ToUserName   string `json:"ToUserName"`
+}
+
+// Ticket 缓存中的 component_ticket
+func (component *Component) Ticket() (ticket string, err error) {
+	ticket, _ = component.Cache.Fetch(component.cacheKey("component_ticket"))
+	if ticket == "" {
+		return "", ErrorNoTicket
+	}
+	return
+}
+
+// SetTicket 缓存 component_ticket，新的 ticket 推送前 一直有效
+func (component *Component) SetTicket(ticket string) error {
+	return component.Cache.Save(component.cacheKey("component_ticket"), ticket, 0)
+}
+
+/*
+TicketHandler 接收 component_ticket 推送 的处理器，校验签名、解密 后 缓存 ticket，应答 success
+
+	http.Handle("/component/ticket", component.TicketHandler())
+*/
+func (component *Component) TicketHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, server.MaxMessageSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		plaintext, err := component.DecryptMessage(body)
+		if err == ErrorSignature || err == ErrorNoToken {
+			component.logf("ticket %v %s", err, body)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			component.logf("ticket %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var message TicketMessage
+		if err = json.Unmarshal(plaintext, &message); err != nil || message.Ticket == "" {
+			component.logf("ticket invalid message %s", plaintext)
+			http.Error(w, "invalid ticket message", http.StatusBadRequest)
+			return
+		}
+
+		if err = component.SetTicket(message.Ticket); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("success"))
+	})
+}
+
+/*
+DecryptMessage 校验 推送消息的签名 并解密
+
+Encrypt 使用 AES-256-CBC 加密，key 为 base64 解码 EncodingAESKey + "=" 得到的 32 字节，iv 为 key 的前 16 字节，PKCS#7 填充；
+明文 = 16 字节随机数 + 4 字节消息长度（大端序）+ 消息 + component_appid；Config.Token 为空时 返回 ErrorNoToken
+*/
+func (component *Component) DecryptMessage(body []byte) (plaintext []byte, err error) {
+	if component.Config.Token == "" {
+		return nil, ErrorNoToken
+	}
+
+	var message EncryptedMessage
+	if err = json.Unmarshal(body, &message); err != nil {
+		return
+	}
+
+	if !server.VerifySignature(message.MsgSignature, component.Config.Token, string(message.TimeStamp), string(message.Nonce), message.Encrypt) {
+		return nil, ErrorSignature
+	}
+
+	key, err := base64.StdEncoding.DecodeString(component.Config.EncodingAESKey + "=")
+	if err != nil {
+		return nil, fmt.Errorf("invalid EncodingAESKey: %v", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(message.Encrypt)
+	if err != nil {
+		return
+	}
+
+	decrypted, err := aesDecrypt(key, ciphertext)
+	if err != nil {
+		return
+	}
+
+	if len(decrypted) < 20 {
+		return nil, ErrorDecrypt
+	}
+	// 先按 uint32 比较，32 位平台上 转换为 int 不会溢出
+	length := binary.BigEndian.Uint32(decrypted[16:20])
+	if uint64(length) > uint64(len(decrypted)-20) {
+		return nil, ErrorDecrypt
+	}
+	end := 20 + int(length)
+	if appid := string(decrypted[end:]); appid != component.Config.ComponentAppId {
+		return nil, ErrorComponentId
+	}
+	return decrypted[20:end], nil
+}
+
+func aesDecrypt(key []byte, ciphertext []byte) (plaintext []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrorDecrypt
+	}
+
+	plaintext = make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, key[:aes.BlockSize]).CryptBlocks(plaintext, ciphertext)
+
+	// PKCS#7
+	padding := int(plaintext[len(plaintext)-1])
+	if padding < 1 || padding > 32 || padding > len(plaintext) || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrorDecrypt
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastwego/microapp/server"
+)
+
+const testEncodingAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+
+// encrypt 按 平台推送 的格式 加密 msg 并签名
+func encrypt(config Config, appid string, msg string) []byte {
+	return encryptWithLength(config, appid, msg, uint32(len(msg)))
+}
+
+// encryptWithLength 同 encrypt，消息长度 使用 length
+func encryptWithLength(config Config, appid string, msg string, length uint32) []byte {
+	key, _ := base64.StdEncoding.DecodeString(config.EncodingAESKey + "=")
+
+	plaintext := bytes.NewBufferString("0123456789abcdef")
+	_ = binary.Write(plaintext, binary.BigEndian, length)
+	plaintext.WriteString(msg)
+	plaintext.WriteString(appid)
+
+	padding := aes.BlockSize - plaintext.Len()%aes.BlockSize
+	plaintext.Write(bytes.Repeat([]byte{byte(padding)}, padding))
+
+	block, _ := aes.NewCipher(key)
+	ciphertext := make([]byte, plaintext.Len())
+	cipher.NewCBCEncrypter(block, key[:aes.BlockSize]).CryptBlocks(ciphertext, plaintext.Bytes())
+
+	encrypted := base64.StdEncoding.EncodeToString(ciphertext)
+	body, _ := json.Marshal(map[string]string{
+		"TimeStamp":    "1602507471",
+		"Nonce":        "797",
+		"Encrypt":      encrypted,
+		"MsgSignature": server.Signature(config.Token, "1602507471", "797", encrypted),
+	})
+	return body
+}
+
+func TestTicketHandler(t *testing.T) {
+	config := Config{ComponentAppId: "COMPONENT_APPID", Token: "TOKEN", EncodingAESKey: testEncodingAESKey}
+	component := newTestComponent(config, "")
+
+	if _, err := component.Ticket(); err != ErrorNoTicket {
+		t.Fatalf("Ticket() before push: err = %v", err)
+	}
+
+	body := encrypt(config, "COMPONENT_APPID", `{"Ticket":"TICKET","MsgType":"Ticket","CreateTime":1602507471}`)
+	w := httptest.NewRecorder()
+	component.TicketHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ticket", bytes.NewReader(body)))
+	if w.Code != http.StatusOK || w.Body.String() != "success" {
+		t.Fatalf("response = %d %s", w.Code, w.Body.String())
+	}
+
+	if ticket, err := component.Ticket(); err != nil || ticket != "TICKET" {
+		t.Errorf("Ticket() = %s, %v", ticket, err)
+	}
+}
+
+func TestTicketHandlerErrors(t *testing.T) {
+	config := Config{ComponentAppId: "COMPONENT_APPID", Token: "TOKEN", EncodingAESKey: testEncodingAESKey}
+	component := newTestComponent(config, "")
+
+	forged := config
+	forged.Token = "OTHER_TOKEN"
+
+	tests := []struct {
+		name string
+		body []byte
+		code int
+	}{
+		{"signature", encrypt(forged, "COMPONENT_APPID", `{"Ticket":"TICKET"}`), http.StatusForbidden},
+		{"appid", encrypt(config, "OTHER_APPID", `{"Ticket":"TICKET"}`), http.StatusBadRequest},
+		{"length", encryptWithLength(config, "COMPONENT_APPID", `{"Ticket":"TICKET"}`, 0xFFFFFFF0), http.StatusBadRequest},
+		{"no ticket", encrypt(config, "COMPONENT_APPID", `{"MsgType":"Ticket"}`), http.StatusBadRequest},
+		{"json", []byte(`not json`), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			component.TicketHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ticket", bytes.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Errorf("code = %d, want %d", w.Code, tt.code)
+			}
+			if _, err := component.Ticket(); err != ErrorNoTicket {
+				t.Errorf("ticket should not be saved, err = %v", err)
+			}
+		})
+	}
+}
+
+func TestTicketHandlerNoToken(t *testing.T) {
+	config := Config{ComponentAppId: "COMPONENT_APPID", EncodingAESKey: testEncodingAESKey}
+	component := newTestComponent(config, "")
+
+	// Token 为空时 签名 只是 TimeStamp Nonce Encrypt 的 sha1，任何人 都能伪造
+	body := encrypt(config, "COMPONENT_APPID", `{"Ticket":"TICKET"}`)
+	if _, err := component.DecryptMessage(body); err != ErrorNoToken {
+		t.Errorf("DecryptMessage() err = %v, want %v", err, ErrorNoToken)
+	}
+
+	w := httptest.NewRecorder()
+	component.TicketHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ticket", bytes.NewReader(body)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("code = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if _, err := component.Ticket(); err != ErrorNoTicket {
+		t.Errorf("ticket should not be saved, err = %v", err)
+	}
+}
@@ -20,6 +20,9 @@ See: https://microapp.bytedance.com/
 package microapp
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
 
@@ -49,8 +52,12 @@ type MicroApp struct {
 小程序配置
 */
 type Config struct {
-	AppId     string
-	AppSecret string
+	AppId        string
+	AppSecret    string
+	PaymentSalt  string // 担保支付 SALT，用于 计算请求签名，见 apis/ecpay
+	PaymentToken string // 担保支付 Token，用于 校验回调签名，见 apis/ecpay
+
+	ComponentAppId string // 第三方平台 appid，代授权小程序 调用接口时 使用，见 component.NewMicroApp
 }
 
 /*
@@ -69,3 +76,14 @@ func New(config Config) (microapp *MicroApp) {
 
 	return &instance
 }
+
+/*
+SessionSignature 用户登录态签名 hmac_sha256(session_key, body)，输出 小写十六进制
+
+数据缓存 等接口 需要在 query 参数 signature 中 携带，sig_method 为 hmac_sha256
+*/
+func SessionSignature(sessionKey string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(sessionKey))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// 消息类型
+const (
+	TypeContentAudit = "content_audit" // 内容审核结果
+	TypeSubscribe    = "subscribe"     // 订阅消息 订阅状态变更
+)
+
+// 内置的 消息类型 => 事件结构
+var defaultEvents = map[string]func() Event{
+	TypeContentAudit: func() Event { return &ContentAuditEvent{} },
+	TypeSubscribe:    func() Event { return &SubscribeEvent{} },
+}
+
+/*
+Message 推送消息
+
+	{"timestamp": "1602507471", "nonce": "797", "msg": "{...}", "type": "payment", "msg_signature": "..."}
+
+msg 为 JSON 字符串，按 type 解析为 事件
+*/
+type Message struct {
+	Timestamp    String `json:"timestamp"`
+	Nonce        String `json:"nonce"`
+	Msg          string `json:"msg"`
+	Type         string `json:"type"`
+	MsgSignature string `json:"msg_signature"`
+}
+
+// Envelope 事件 所在的推送消息
+func (message Message) Envelope() Message {
+	return message
+}
+
+func (message *Message) setMessage(m Message) {
+	*message = m
+}
+
+// String 兼容 JSON 字符串 和 数值，如 timestamp 可能为 "1602507471" 或 1602507471
+type String string
+
+func (s *String) UnmarshalJSON(data []byte) (err error) {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var value string
+		err = json.Unmarshal(data, &value)
+		*s = String(value)
+		return
+	}
+
+	var number json.Number
+	err = json.Unmarshal(data, &number)
+	*s = String(number)
+	return
+}
+
+/*
+Event 推送消息 解析后的事件
+
+内置事件 为 *ContentAuditEvent *SubscribeEvent，未注册的消息类型 为 *RawEvent，可使用 Server.RegisterEvent 注册新的事件结构
+（结构中 嵌入 Message 并使用 json:"-" 标签 即可）
+*/
+type Event interface {
+	Envelope() Message
+	setMessage(message Message)
+}
+
+// RawEvent 未注册的消息类型，msg 未解析
+type RawEvent struct {
+	Message
+}
+
+/*
+ContentAuditEvent 内容审核结果
+*/
+type ContentAuditEvent struct {
+	Message `json:"-"`
+
+	AppId    string `json:"app_id"`
+	TaskId   string `json:"task_id"`
+	DataId   string `json:"data_id"`
+	Predicts []struct {
+		Target    string  `json:"target"`
+		ModelName string  `json:"model_name"`
+		Hit       bool    `json:"hit"`
+		Prob      float64 `json:"prob"`
+	} `json:"predicts"`
+}
+
+/*
+SubscribeEvent 用户 订阅 或 取消订阅 订阅消息模板
+*/
+type SubscribeEvent struct {
+	Message `json:"-"`
+
+	AppId     string `json:"app_id"`
+	OpenId    string `json:"open_id"`
+	TplId     string `json:"tpl_id"`
+	Status    string `json:"status"` // subscribe 订阅 unsubscribe 取消订阅
+	EventTime int64  `json:"event_time"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fastwego/microapp/server"
+)
+
+func ExampleServer() {
+	srv := server.New("TOKEN")
+
+	srv.Handle(server.TypeContentAudit, func(event server.Event) error {
+		audit := event.(*server.ContentAuditEvent)
+		fmt.Println(audit.TaskId, audit.Predicts)
+		return nil
+	})
+
+	srv.Handle(server.TypeSubscribe, func(event server.Event) error {
+		subscribe := event.(*server.SubscribeEvent)
+		fmt.Println(subscribe.OpenId, subscribe.Status)
+		return nil
+	})
+
+	http.Handle("/callback", srv)
+}
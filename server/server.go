@@ -0,0 +1,268 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package server 消息推送 服务，接收 字节服务器 推送的 内容审核结果、支付通知、订阅事件 等消息
+
+	srv := server.New("TOKEN")
+	srv.Handle(server.TypeSubscribe, func(event server.Event) error {
+		subscribe := event.(*server.SubscribeEvent)
+		...
+		return nil
+	})
+	http.Handle("/callback", srv)
+
+GET 请求 为 开发者服务器 配置校验，校验签名后 原样返回 echostr；
+POST 请求 为 推送的消息，校验 msg_signature 后 按 type 解析为 事件 并分发到 注册的处理器
+
+See: https://microapp.bytedance.com/docs/zh-CN/mini-app/develop/server/server-api-introduction
+*/
+package server
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaxMessageSize 推送消息 请求体的 最大字节数，超过时 应答失败
+var MaxMessageSize int64 = 1 << 20
+
+var (
+	ErrorSignature   = errors.New("signature mismatch")
+	ErrorNoToken     = errors.New("token not configured")
+	ErrorNoHandler   = errors.New("no handler")
+	ErrorMessageType = errors.New("unknown message type")
+)
+
+// HandlerFunc 消息处理器，返回错误时 应答失败，字节服务器 会重新推送
+type HandlerFunc func(event Event) error
+
+/*
+Server 消息推送 服务，实现 http.Handler
+
+Token 为空时 无法校验签名，所有请求 均返回 403
+*/
+type Server struct {
+	Token  string // 开发者后台 消息推送 配置的 Token
+	Logger *log.Logger
+
+	// Default 未注册处理器的消息类型 使用的处理器，为空时 应答失败
+	Default HandlerFunc
+
+	mutex    sync.RWMutex
+	handlers map[string]HandlerFunc
+	events   map[string]func() Event
+}
+
+/*
+创建 消息推送 服务
+*/
+func New(token string) (server *Server) {
+	server = &Server{
+		Token:    token,
+		handlers: map[string]HandlerFunc{},
+		events:   map[string]func() Event{},
+	}
+	for msgType, newEvent := range defaultEvents {
+		server.events[msgType] = newEvent
+	}
+	return
+}
+
+// Handle 注册 消息类型 msgType 的处理器
+func (server *Server) Handle(msgType string, handler HandlerFunc) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.handlers[msgType] = handler
+}
+
+/*
+RegisterEvent 注册 消息类型 msgType 对应的事件结构
+
+推送消息的 msg 使用 json 解析到 newEvent 返回的结构中，未注册的消息类型 解析为 *RawEvent
+*/
+func (server *Server) RegisterEvent(msgType string, newEvent func() Event) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.events[msgType] = newEvent
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		server.serveEchostr(w, r)
+	case http.MethodPost:
+		server.serveMessage(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveEchostr 开发者服务器 配置校验
+func (server *Server) serveEchostr(w http.ResponseWriter, r *http.Request) {
+	if server.Token == "" {
+		server.logf("echostr %v", ErrorNoToken)
+		http.Error(w, ErrorNoToken.Error(), http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	if !VerifySignature(query.Get("signature"), server.Token, query.Get("timestamp"), query.Get("nonce")) {
+		server.logf("echostr %v", ErrorSignature)
+		http.Error(w, ErrorSignature.Error(), http.StatusForbidden)
+		return
+	}
+
+	_, _ = w.Write([]byte(query.Get("echostr")))
+}
+
+// serveMessage 校验 推送消息的签名，分发 到处理器，并应答
+func (server *Server) serveMessage(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxMessageSize))
+	if err != nil {
+		server.ack(w, err)
+		return
+	}
+
+	message, err := server.ParseMessage(body)
+	if err == ErrorSignature || err == ErrorNoToken {
+		server.logf("message %v %s", err, body)
+		writeAck(w, http.StatusForbidden, Ack{ErrNo: 1, ErrTips: err.Error()})
+		return
+	}
+	if err != nil {
+		server.ack(w, err)
+		return
+	}
+
+	event, err := server.ParseEvent(message)
+	if err == nil {
+		err = server.dispatch(event)
+	}
+	server.ack(w, err)
+}
+
+/*
+ParseMessage 解析 推送消息 并校验签名
+
+msg_signature = sha1(将 Token timestamp nonce msg 按字典序排序后 拼接)；Token 为空时 返回 ErrorNoToken
+*/
+func (server *Server) ParseMessage(body []byte) (message Message, err error) {
+	if server.Token == "" {
+		return message, ErrorNoToken
+	}
+
+	if err = json.Unmarshal(body, &message); err != nil {
+		return
+	}
+
+	if !VerifySignature(message.MsgSignature, server.Token, string(message.Timestamp), string(message.Nonce), message.Msg) {
+		return message, ErrorSignature
+	}
+	return
+}
+
+// ParseEvent 按 消息类型 解析 msg
+func (server *Server) ParseEvent(message Message) (event Event, err error) {
+	server.mutex.RLock()
+	newEvent, ok := server.events[message.Type]
+	server.mutex.RUnlock()
+
+	if !ok {
+		return &RawEvent{Message: message}, nil
+	}
+
+	event = newEvent()
+	if err = json.Unmarshal([]byte(message.Msg), event); err != nil {
+		return nil, fmt.Errorf("%s msg: %v", message.Type, err)
+	}
+	event.setMessage(message)
+	return
+}
+
+func (server *Server) dispatch(event Event) (err error) {
+	msgType := event.Envelope().Type
+
+	server.mutex.RLock()
+	handler, ok := server.handlers[msgType]
+	server.mutex.RUnlock()
+
+	if !ok {
+		handler = server.Default
+	}
+	if handler == nil {
+		return fmt.Errorf("%w for message type %s", ErrorNoHandler, msgType)
+	}
+	return handler(event)
+}
+
+/*
+ack 应答 推送消息
+
+	{"err_no": 0, "err_tips": "success"}
+
+处理失败时 err_no 不为 0，字节服务器 会重新推送；签名错误 或 未配置 Token 同样应答 Ack，状态码为 403
+*/
+func (server *Server) ack(w http.ResponseWriter, err error) {
+	result := Ack{ErrNo: 0, ErrTips: "success"}
+	if err != nil {
+		server.logf("message %v", err)
+		result = Ack{ErrNo: 1, ErrTips: err.Error()}
+	}
+	writeAck(w, http.StatusOK, result)
+}
+
+func writeAck(w http.ResponseWriter, status int, result Ack) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func (server *Server) logf(format string, v ...interface{}) {
+	if server.Logger != nil {
+		server.Logger.Printf(format, v...)
+	}
+}
+
+// Ack 推送消息的应答
+type Ack struct {
+	ErrNo   int    `json:"err_no"`
+	ErrTips string `json:"err_tips"`
+}
+
+// VerifySignature 校验 signature 是否为 values 的签名，使用 常量时间 比较
+func VerifySignature(signature string, values ...string) bool {
+	return subtle.ConstantTimeCompare([]byte(Signature(values...)), []byte(signature)) == 1
+}
+
+// Signature 将 values 按字典序排序后 拼接，计算 sha1
+func Signature(values ...string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	sum := sha1.Sum([]byte(strings.Join(sorted, "")))
+	return hex.EncodeToString(sum[:])
+}
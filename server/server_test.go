@@ -0,0 +1,222 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testToken = "TOKEN"
+
+// signedMessage 使用 testToken 签名的 推送消息
+func signedMessage(msgType string, msg string) string {
+	body, _ := json.Marshal(map[string]string{
+		"timestamp":     "1602507471",
+		"nonce":         "797",
+		"msg":           msg,
+		"type":          msgType,
+		"msg_signature": Signature(testToken, "1602507471", "797", msg),
+	})
+	return string(body)
+}
+
+func post(srv *Server, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body)))
+	return w
+}
+
+func TestSignature(t *testing.T) {
+	// 排序后 拼接 的 sha1，与参数顺序无关
+	if got, want := Signature("b", "a", "c"), "a9993e364706816aba3e25717850c26c9cd0d89d"; got != want {
+		t.Errorf("Signature() = %s, want %s", got, want)
+	}
+	if Signature("c", "b", "a") != Signature("a", "b", "c") {
+		t.Error("Signature() depends on argument order")
+	}
+
+	if !VerifySignature(Signature("a", "b", "c"), "c", "b", "a") {
+		t.Error("VerifySignature() rejected a valid signature")
+	}
+	if VerifySignature("", "a", "b", "c") || VerifySignature(strings.ToUpper(Signature("a", "b", "c")), "a", "b", "c") {
+		t.Error("VerifySignature() accepted an invalid signature")
+	}
+}
+
+func TestServeEchostr(t *testing.T) {
+	srv := New(testToken)
+
+	tests := []struct {
+		name      string
+		signature string
+		status    int
+		body      string
+	}{
+		{name: "valid", signature: Signature(testToken, "1602507471", "797"), status: http.StatusOK, body: "ECHOSTR"},
+		{name: "invalid", signature: "bad", status: http.StatusForbidden, body: ErrorSignature.Error() + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{"signature": {tt.signature}, "timestamp": {"1602507471"}, "nonce": {"797"}, "echostr": {"ECHOSTR"}}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callback?"+query.Encode(), nil))
+
+			if w.Code != tt.status || w.Body.String() != tt.body {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+		})
+	}
+}
+
+func TestServeMessage(t *testing.T) {
+	srv := New(testToken)
+
+	var got *SubscribeEvent
+	srv.Handle(TypeSubscribe, func(event Event) error {
+		got = event.(*SubscribeEvent)
+		return nil
+	})
+
+	w := post(srv, signedMessage(TypeSubscribe, `{"app_id":"APPID","open_id":"OPENID","tpl_id":"TPL","status":"subscribe","event_time":1602507471}`))
+	if w.Code != http.StatusOK || w.Body.String() != `{"err_no":0,"err_tips":"success"}`+"\n" {
+		t.Fatalf("ack = %d %s", w.Code, w.Body.String())
+	}
+	if got == nil || got.OpenId != "OPENID" || got.Status != "subscribe" || got.EventTime != 1602507471 {
+		t.Fatalf("event = %+v", got)
+	}
+	if got.Envelope().Type != TypeSubscribe || got.Envelope().Timestamp != "1602507471" {
+		t.Errorf("envelope = %+v", got.Envelope())
+	}
+}
+
+func TestServeMessageErrors(t *testing.T) {
+	srv := New(testToken)
+	srv.Handle(TypeContentAudit, func(event Event) error {
+		return errors.New("busy")
+	})
+
+	tamperedBody := strings.Replace(signedMessage(TypeSubscribe, `{"open_id":"OPENID"}`), "OPENID", "OTHER", 1)
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		errNo  int
+		tips   string
+	}{
+		{name: "tampered", body: tamperedBody, status: http.StatusForbidden, errNo: 1, tips: ErrorSignature.Error()},
+		{name: "invalid json", body: "{", status: http.StatusOK, errNo: 1},
+		{name: "too large", body: strings.Repeat(" ", int(MaxMessageSize)+1), status: http.StatusOK, errNo: 1, tips: "too large"},
+		{name: "invalid msg", body: signedMessage(TypeSubscribe, "{"), status: http.StatusOK, errNo: 1, tips: "subscribe msg"},
+		{name: "no handler", body: signedMessage(TypeSubscribe, "{}"), status: http.StatusOK, errNo: 1, tips: ErrorNoHandler.Error()},
+		{name: "handler error", body: signedMessage(TypeContentAudit, "{}"), status: http.StatusOK, errNo: 1, tips: "busy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(srv, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+
+			var ack Ack
+			if err := json.Unmarshal(w.Body.Bytes(), &ack); err != nil {
+				t.Fatal(err)
+			}
+			if ack.ErrNo != tt.errNo || !strings.Contains(ack.ErrTips, tt.tips) {
+				t.Errorf("ack = %+v, want err_no %d with %q", ack, tt.errNo, tt.tips)
+			}
+		})
+	}
+}
+
+func TestNoToken(t *testing.T) {
+	srv := New("")
+	srv.Default = func(event Event) error {
+		t.Errorf("dispatched %+v without token", event)
+		return nil
+	}
+
+	// Token 为空时 签名 只是 timestamp nonce msg 的 sha1，任何人 都能伪造
+	query := url.Values{"signature": {Signature("1602507471", "797")}, "timestamp": {"1602507471"}, "nonce": {"797"}, "echostr": {"ECHOSTR"}}
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callback?"+query.Encode(), nil))
+	if w.Code != http.StatusForbidden || w.Body.String() != ErrorNoToken.Error()+"\n" {
+		t.Errorf("echostr = %d %q, want 403", w.Code, w.Body.String())
+	}
+
+	msg := `{"open_id":"OPENID"}`
+	body, _ := json.Marshal(map[string]string{
+		"timestamp":     "1602507471",
+		"nonce":         "797",
+		"msg":           msg,
+		"type":          TypeSubscribe,
+		"msg_signature": Signature("1602507471", "797", msg),
+	})
+	if _, err := srv.ParseMessage(body); err != ErrorNoToken {
+		t.Errorf("ParseMessage() err = %v, want %v", err, ErrorNoToken)
+	}
+
+	w = post(srv, string(body))
+	var ack Ack
+	_ = json.Unmarshal(w.Body.Bytes(), &ack)
+	if w.Code != http.StatusForbidden || ack.ErrNo != 1 || ack.ErrTips != ErrorNoToken.Error() {
+		t.Errorf("ack = %d %+v, want 403 with %v", w.Code, ack, ErrorNoToken)
+	}
+}
+
+type customEvent struct {
+	Message `json:"-"`
+
+	Value string `json:"value"`
+}
+
+func TestRegisterEvent(t *testing.T) {
+	srv := New(testToken)
+	srv.RegisterEvent("custom", func() Event { return &customEvent{} })
+
+	var events []Event
+	srv.Default = func(event Event) error {
+		events = append(events, event)
+		return nil
+	}
+
+	post(srv, signedMessage("custom", `{"value":"VALUE"}`))
+	post(srv, signedMessage("unknown", `{"value":"VALUE"}`))
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if custom, ok := events[0].(*customEvent); !ok || custom.Value != "VALUE" {
+		t.Errorf("custom event = %#v", events[0])
+	}
+	if raw, ok := events[1].(*RawEvent); !ok || raw.Msg != `{"value":"VALUE"}` {
+		t.Errorf("raw event = %#v", events[1])
+	}
+}
+
+func TestMessageTimestamp(t *testing.T) {
+	var message Message
+	if err := json.Unmarshal([]byte(`{"timestamp":1602507471,"nonce":"797"}`), &message); err != nil {
+		t.Fatal(err)
+	}
+	if message.Timestamp != "1602507471" || message.Nonce != "797" {
+		t.Errorf("message = %+v", message)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fastwego/microapp"
+)
+
+// CapturedRequest 捕获的请求
+type CapturedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON 解析 JSON 请求体
+func (req CapturedRequest) JSON() (data map[string]interface{}, err error) {
+	err = json.Unmarshal(req.Body, &data)
+	return
+}
+
+/*
+JSONField 按路径读取 JSON 请求体中的字段
+
+路径以 . 分隔，数组使用下标，如 tasks.0.content
+*/
+func (req CapturedRequest) JSONField(field string) (value interface{}, err error) {
+	var data interface{}
+	if err = json.Unmarshal(req.Body, &data); err != nil {
+		return
+	}
+
+	value = data
+	for _, key := range strings.Split(field, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return nil, fmt.Errorf("field %s not found", field)
+			}
+		case []interface{}:
+			i, e := strconv.Atoi(key)
+			if e != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("field %s not found", field)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("field %s not found", field)
+		}
+	}
+	return
+}
+
+// AccessToken 请求携带的 access_token，依次查找 query 参数、X-Token 请求头、JSON 请求体
+func (req CapturedRequest) AccessToken() string {
+	if accessToken := req.Query.Get("access_token"); accessToken != "" {
+		return accessToken
+	}
+	if accessToken := req.Header.Get("X-Token"); accessToken != "" {
+		return accessToken
+	}
+	if value, err := req.JSONField("access_token"); err == nil {
+		if accessToken, ok := value.(string); ok {
+			return accessToken
+		}
+	}
+	return ""
+}
+
+/*
+RequestCapture 请求捕获
+
+记录每个路径 最后一次收到的请求，用于断言 SDK 实际发出的内容
+
+	test.MockCapture.AssertMethod(t, apiNotify, http.MethodPost)
+	test.MockCapture.AssertJSONField(t, apiNotify, "tpl_id", "TPL_ID")
+*/
+type RequestCapture struct {
+	next  http.Handler
+	mutex sync.Mutex
+	last  map[string]CapturedRequest
+}
+
+// NewRequestCapture 创建 请求捕获，请求记录后交给 next 处理
+func NewRequestCapture(next http.Handler) *RequestCapture {
+	return &RequestCapture{next: next, last: map[string]CapturedRequest{}}
+}
+
+// ServeHTTP 实现 http.Handler
+func (capture *RequestCapture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	capture.mutex.Lock()
+	capture.last[r.URL.Path] = CapturedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+	capture.mutex.Unlock()
+
+	capture.next.ServeHTTP(w, r)
+}
+
+// LastRequest 路径 path 最后一次收到的请求
+func (capture *RequestCapture) LastRequest(path string) (req CapturedRequest, ok bool) {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	req, ok = capture.last[path]
+	return
+}
+
+// Reset 清除 已捕获的请求
+func (capture *RequestCapture) Reset() {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	capture.last = map[string]CapturedRequest{}
+}
+
+// MustLastRequest 路径 path 最后一次收到的请求，没有时 测试失败
+func (capture *RequestCapture) MustLastRequest(t testing.TB, path string) CapturedRequest {
+	t.Helper()
+
+	req, ok := capture.LastRequest(path)
+	if !ok {
+		t.Fatalf("no request captured for %s", path)
+	}
+	return req
+}
+
+// AssertMethod 断言 请求方法
+func (capture *RequestCapture) AssertMethod(t testing.TB, path string, want string) {
+	t.Helper()
+
+	if got := capture.MustLastRequest(t, path).Method; got != want {
+		t.Errorf("%s method = %v, want %v", path, got, want)
+	}
+}
+
+// AssertQuery 断言 query 参数
+func (capture *RequestCapture) AssertQuery(t testing.TB, path string, key string, want string) {
+	t.Helper()
+
+	query := capture.MustLastRequest(t, path).Query
+	if _, ok := query[key]; !ok {
+		t.Errorf("%s query %s not found", path, key)
+		return
+	}
+	if got := query.Get(key); got != want {
+		t.Errorf("%s query %s = %v, want %v", path, key, got, want)
+	}
+}
+
+// AssertHeader 断言 请求头
+func (capture *RequestCapture) AssertHeader(t testing.TB, path string, key string, want string) {
+	t.Helper()
+
+	if got := capture.MustLastRequest(t, path).Header.Get(key); got != want {
+		t.Errorf("%s header %s = %v, want %v", path, key, got, want)
+	}
+}
+
+/*
+AssertJSONField 断言 JSON 请求体中的字段
+
+want 经过 JSON 编解码后比较，因此数字可以直接使用 int 等类型
+*/
+func (capture *RequestCapture) AssertJSONField(t testing.TB, path string, field string, want interface{}) {
+	t.Helper()
+
+	got, err := capture.MustLastRequest(t, path).JSONField(field)
+	if err != nil {
+		t.Errorf("%s body: %v", path, err)
+		return
+	}
+
+	var normalized interface{}
+	data, err := json.Marshal(want)
+	if err == nil {
+		err = json.Unmarshal(data, &normalized)
+	}
+	if err != nil {
+		t.Errorf("%s body %s: bad want value %v", path, field, want)
+		return
+	}
+
+	if !reflect.DeepEqual(got, normalized) {
+		t.Errorf("%s body %s = %v, want %v", path, field, got, want)
+	}
+}
+
+// AssertAccessToken 断言 请求携带了 小程序实例 当前的 access_token
+func (capture *RequestCapture) AssertAccessToken(t testing.TB, path string, ctx *microapp.MicroApp) {
+	t.Helper()
+
+	want, err := ctx.GetAccessTokenHandler(ctx)
+	if err != nil {
+		t.Errorf("GetAccessTokenHandler() error = %v", err)
+		return
+	}
+
+	if got := capture.MustLastRequest(t, path).AccessToken(); got != want {
+		t.Errorf("%s access_token = %v, want %v", path, got, want)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/microapp"
+)
+
+func TestRequestCapture(t *testing.T) {
+	platform := NewFakePlatform("APPID", "SECRET")
+	capture := NewRequestCapture(platform)
+	svr := httptest.NewServer(capture)
+	defer svr.Close()
+
+	serverUrl := microapp.ServerUrl
+	microapp.ServerUrl = svr.URL
+	defer func() { microapp.ServerUrl = serverUrl }()
+
+	app := microapp.New(microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+	app.Cache = sync.New()
+	app.Logger = nil
+
+	accessToken, err := app.GetAccessTokenHandler(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// query
+	if _, err = app.Client.HTTPGet("/api/apps/jscode2session?appid=APPID&secret=SECRET&code=CODE"); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"kv_list":[{"key":"k","value":"v"}]}`
+	mac := hmac.New(sha256.New, []byte(platform.SessionKey("OPENID_CODE")))
+	_, _ = mac.Write([]byte(body))
+
+	params := url.Values{}
+	params.Add("access_token", accessToken)
+	params.Add("openid", "OPENID_CODE")
+	params.Add("signature", hex.EncodeToString(mac.Sum(nil)))
+	params.Add("sig_method", "hmac_sha256")
+	if _, err = app.Client.HTTPPost("/api/apps/set_user_storage?"+params.Encode(), strings.NewReader(body), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+	capture.AssertMethod(t, "/api/apps/set_user_storage", http.MethodPost)
+	capture.AssertQuery(t, "/api/apps/set_user_storage", "sig_method", "hmac_sha256")
+	capture.AssertHeader(t, "/api/apps/set_user_storage", "Content-Type", "application/json")
+	capture.AssertJSONField(t, "/api/apps/set_user_storage", "kv_list.0.value", "v")
+	capture.AssertAccessToken(t, "/api/apps/set_user_storage", app)
+
+	// header
+	req, _ := http.NewRequest(http.MethodPost, svr.URL+"/api/v2/tags/text/antidirt", strings.NewReader(`{"tasks":[{"content":"hello"}]}`))
+	req.Header.Set("X-Token", accessToken)
+	if _, err = app.Client.HTTPDo(req); err != nil {
+		t.Fatal(err)
+	}
+	capture.AssertHeader(t, "/api/v2/tags/text/antidirt", "X-Token", accessToken)
+	capture.AssertAccessToken(t, "/api/v2/tags/text/antidirt", app)
+
+	// body
+	if _, err = app.Client.HTTPPost("/api/apps/qrcode", strings.NewReader(`{"access_token":"`+accessToken+`","width":64}`), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+	capture.AssertJSONField(t, "/api/apps/qrcode", "width", 64)
+	capture.AssertAccessToken(t, "/api/apps/qrcode", app)
+
+	data, err := capture.MustLastRequest(t, "/api/apps/qrcode").JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, map[string]interface{}{"access_token": accessToken, "width": float64(64)}) {
+		t.Errorf("JSON() = %v", data)
+	}
+
+	if _, ok := capture.LastRequest("/api/apps/remove_user_storage"); ok {
+		t.Error("LastRequest() found request never sent")
+	}
+	capture.Reset()
+	if _, ok := capture.LastRequest("/api/apps/qrcode"); ok {
+		t.Error("LastRequest() found request after Reset()")
+	}
+}
+
+func TestCapturedRequestJSONField(t *testing.T) {
+	req := CapturedRequest{Body: []byte(`{"tasks":[{"content":"hello"}],"n":1}`)}
+
+	tests := []struct {
+		name    string
+		field   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "nested", field: "tasks.0.content", want: "hello"},
+		{name: "number", field: "n", want: float64(1)},
+		{name: "missing", field: "tasks.1.content", wantErr: true},
+		{name: "not object", field: "n.x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := req.JSONField(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSONField() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JSONField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
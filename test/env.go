@@ -0,0 +1,114 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/microapp"
+)
+
+/*
+Env 独立的测试环境
+
+每个环境拥有 独立的 模拟服务器、小程序实例 和 缓存，不依赖全局 ServerUrl，可以在 t.Parallel 的测试中使用
+
+	func TestXxx(t *testing.T) {
+		t.Parallel()
+		env := test.NewEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+		env.Mux.HandleFunc(apiXxx, func(w http.ResponseWriter, r *http.Request) { ... })
+		resp, err := Xxx(env.MicroApp, payload)
+		env.Capture.AssertAccessToken(t, apiXxx, env.MicroApp)
+	}
+*/
+type Env struct {
+	MicroApp *microapp.MicroApp
+	Server   *httptest.Server
+	Mux      *http.ServeMux
+	Faults   *FaultInjector
+	Capture  *RequestCapture
+	Platform *FakePlatform // 仅 NewPlatformEnv 创建的环境 不为空
+}
+
+/*
+NewEnv 创建 绑定到 t 的独立测试环境，测试结束时自动关闭模拟服务器
+
+config 未设置 AppId 时 使用 APPID/SECRET；模拟服务器 颁发固定的 ACCESS_TOKEN，
+未在 Mux 上单独注册的路径 由 模拟接口 处理，见 MockApiHandler
+*/
+func NewEnv(t testing.TB, config microapp.Config) *Env {
+	env := newEnv(config)
+	env.Mux.HandleFunc("/api/apps/token", mockAccessToken)
+	env.Mux.Handle("/", MockApiHandler())
+	env.bind(t)
+
+	return env
+}
+
+// NewPlatformEnv 创建 以 FakePlatform 作为模拟服务器 的独立测试环境
+func NewPlatformEnv(t testing.TB, config microapp.Config) *Env {
+	env := newEnv(config)
+	env.Platform = NewFakePlatform(env.MicroApp.Config.AppId, env.MicroApp.Config.AppSecret)
+	env.Platform.Register(env.Mux)
+	env.bind(t)
+
+	return env
+}
+
+func newEnv(config microapp.Config) *Env {
+	if config.AppId == "" {
+		config.AppId = "APPID"
+		config.AppSecret = "SECRET"
+	}
+
+	env := &Env{Mux: http.NewServeMux()}
+	env.Faults = NewFaultInjector(env.Mux)
+	env.Capture = NewRequestCapture(env.Faults)
+	env.Server = httptest.NewServer(env.Capture)
+
+	env.MicroApp = microapp.New(config)
+	env.MicroApp.Cache = sync.New()
+	env.MicroApp.Client.ServerUrl = env.Server.URL
+
+	return env
+}
+
+// bind 日志输出到 t.Log，测试结束时 关闭模拟服务器
+func (env *Env) bind(t testing.TB) {
+	env.MicroApp.Logger = log.New(testLogWriter{t}, "[fastwego/microapp] ", 0)
+
+	t.Cleanup(func() {
+		env.MicroApp.Logger = nil
+		env.Server.Close()
+	})
+}
+
+type testLogWriter struct {
+	t testing.TB
+}
+
+func (writer testLogWriter) Write(p []byte) (n int, err error) {
+	writer.t.Log(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+func mockAccessToken(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+}
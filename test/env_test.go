@@ -0,0 +1,86 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/microapp"
+)
+
+func TestNewEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		config microapp.Config
+		resp   string
+	}{
+		{name: "app1", config: microapp.Config{AppId: "APPID1", AppSecret: "SECRET1"}, resp: `{"errcode":0,"errmsg":"app1"}`},
+		{name: "app2", config: microapp.Config{AppId: "APPID2", AppSecret: "SECRET2"}, resp: `{"errcode":0,"errmsg":"app2"}`},
+		{name: "default", resp: `{"errcode":0,"errmsg":"default"}`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewEnv(t, tt.config)
+			env.Mux.HandleFunc("/api/apps/game/template/send", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.resp))
+			})
+
+			accessToken, err := env.MicroApp.GetAccessTokenHandler(env.MicroApp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := env.MicroApp.Client.HTTPPost("/api/apps/game/template/send", strings.NewReader(`{"access_token":"`+accessToken+`"}`), "application/json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(resp) != tt.resp {
+				t.Errorf("resp = %s, want %s", resp, tt.resp)
+			}
+
+			req := env.Capture.MustLastRequest(t, "/api/apps/token")
+			if tt.config.AppId != "" && req.Query.Get("appid") != tt.config.AppId {
+				t.Errorf("appid = %v, want %v", req.Query.Get("appid"), tt.config.AppId)
+			}
+			env.Capture.AssertAccessToken(t, "/api/apps/game/template/send", env.MicroApp)
+		})
+	}
+}
+
+func TestNewPlatformEnv(t *testing.T) {
+	t.Parallel()
+
+	env := NewPlatformEnv(t, microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+	env.Faults.Inject("/api/apps/qrcode", Fault{Errcode: 40002})
+
+	accessToken, err := env.MicroApp.GetAccessTokenHandler(env.MicroApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := env.MicroApp.Client.HTTPPost("/api/apps/qrcode", strings.NewReader(`{"access_token":"`+accessToken+`"}`), "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(resp), "\x89PNG") {
+		t.Errorf("resp is not png")
+	}
+	if got := env.Platform.Calls("/api/apps/token"); got != 2 {
+		t.Errorf("token calls = %v, want 2", got)
+	}
+}
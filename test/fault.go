@@ -0,0 +1,177 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+Fault 故障
+
+各项可以组合使用：先等待 Latency，再按 ResetConn > Status > Malformed > Errcode 的顺序 返回故障响应；
+只设置 Latency 时 等待后 交给原路由处理
+*/
+type Fault struct {
+	Latency   time.Duration // 响应延迟
+	ResetConn bool          // 直接断开连接
+	Status    int           // 返回 HTTP 状态码
+	Malformed bool          // 返回 非法 JSON
+	Errcode   int           // 返回 接口错误码
+	Times     int           // 接下来 N 次调用生效，默认 1 次
+}
+
+type pendingFault struct {
+	fault Fault
+	times int
+}
+
+/*
+FaultInjector 故障注入
+
+包装 mux 上的路由，对指定路径的 接下来 N 次调用 注入故障
+
+	test.MockFaults.Inject("/api/apps/qrcode", test.Fault{Errcode: 40002})
+*/
+type FaultInjector struct {
+	next   http.Handler
+	mutex  sync.Mutex
+	faults map[string][]*pendingFault
+	calls  map[string]int
+}
+
+// NewFaultInjector 创建 故障注入，未命中故障的请求交给 next 处理
+func NewFaultInjector(next http.Handler) *FaultInjector {
+	return &FaultInjector{
+		next:   next,
+		faults: map[string][]*pendingFault{},
+		calls:  map[string]int{},
+	}
+}
+
+// Inject 为路径 path 注入故障，同一路径多次注入时 按注入顺序依次生效
+func (injector *FaultInjector) Inject(path string, fault Fault) {
+	times := fault.Times
+	if times <= 0 {
+		times = 1
+	}
+
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	injector.faults[path] = append(injector.faults[path], &pendingFault{fault: fault, times: times})
+}
+
+// Clear 清除 所有未生效的故障
+func (injector *FaultInjector) Clear() {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	injector.faults = map[string][]*pendingFault{}
+}
+
+// Pending 路径 path 剩余 未生效的故障次数
+func (injector *FaultInjector) Pending(path string) (n int) {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	for _, pending := range injector.faults[path] {
+		n += pending.times
+	}
+	return
+}
+
+// Calls 路径 path 收到的请求次数（含 注入故障的请求）
+func (injector *FaultInjector) Calls(path string) int {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	return injector.calls[path]
+}
+
+// take 取出 路径 path 当前生效的故障
+func (injector *FaultInjector) take(path string) (fault Fault, ok bool) {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	injector.calls[path]++
+
+	queue := injector.faults[path]
+	if len(queue) == 0 {
+		return
+	}
+
+	pending := queue[0]
+	pending.times--
+	if pending.times <= 0 {
+		injector.faults[path] = queue[1:]
+	}
+
+	return pending.fault, true
+}
+
+// ServeHTTP 实现 http.Handler
+func (injector *FaultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fault, ok := injector.take(r.URL.Path)
+	if !ok {
+		injector.next.ServeHTTP(w, r)
+		return
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case fault.ResetConn:
+		resetConn(w)
+	case fault.Status != 0:
+		w.WriteHeader(fault.Status)
+		_, _ = fmt.Fprintf(w, `{"errcode":%d,"errmsg":"injected status %d"}`, fault.Status, fault.Status)
+	case fault.Malformed:
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":`))
+	case fault.Errcode != 0:
+		writeJSON(w, map[string]interface{}{"errcode": fault.Errcode, "errmsg": "injected errcode"})
+	default:
+		injector.next.ServeHTTP(w, r)
+	}
+}
+
+// resetConn 以 RST 方式断开连接
+func resetConn(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic("test: ResponseWriter does not support hijacking")
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
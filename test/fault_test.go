@@ -0,0 +1,93 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/microapp"
+)
+
+func TestFaultInjector(t *testing.T) {
+	const apiSend = "/api/apps/game/template/send"
+
+	platform := NewFakePlatform("APPID", "SECRET")
+	injector := NewFaultInjector(platform)
+	svr := httptest.NewServer(injector)
+	defer svr.Close()
+
+	serverUrl := microapp.ServerUrl
+	microapp.ServerUrl = svr.URL
+	defer func() { microapp.ServerUrl = serverUrl }()
+
+	app := microapp.New(microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+	app.Cache = sync.New()
+	app.Logger = nil
+	app.Client.HTTPClient = &http.Client{Timeout: 100 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		faults    []Fault
+		wantErr   bool
+		wantErrIs error
+		wantCalls int
+	}{
+		{name: "no fault", wantCalls: 1},
+		{name: "latency", faults: []Fault{{Latency: 10 * time.Millisecond}}, wantCalls: 1},
+		{name: "timeout", faults: []Fault{{Latency: time.Second}}, wantErr: true, wantCalls: 1},
+		{name: "reset", faults: []Fault{{ResetConn: true}}, wantErr: true, wantCalls: 1},
+		{name: "status 502", faults: []Fault{{Status: http.StatusBadGateway}}, wantErr: true, wantCalls: 1},
+		{name: "malformed", faults: []Fault{{Malformed: true}}, wantErr: true, wantCalls: 1},
+		{name: "errcode", faults: []Fault{{Errcode: 40001}}, wantErr: true, wantCalls: 1},
+		{name: "token expire retry", faults: []Fault{{Errcode: 40002}}, wantCalls: 2},
+		{name: "unauthorized retry", faults: []Fault{{Status: http.StatusUnauthorized}}, wantCalls: 2},
+		{name: "busy retry", faults: []Fault{{Errcode: -1}}, wantCalls: 2},
+		{name: "busy twice", faults: []Fault{{Errcode: -1, Times: 2}}, wantErr: true, wantErrIs: microapp.ErrorSystemBusy, wantCalls: 2},
+		{name: "busy then expire", faults: []Fault{{Errcode: -1}, {Errcode: 40002}}, wantErr: true, wantErrIs: microapp.ErrorAccessTokenExpire, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector.Clear()
+			for _, fault := range tt.faults {
+				injector.Inject(apiSend, fault)
+			}
+			before := injector.Calls(apiSend)
+
+			accessToken, err := app.GetAccessTokenHandler(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload := `{"access_token":"` + accessToken + `","touser":"OPENID","template_id":"TPL"}`
+			_, err = app.Client.HTTPPost(apiSend, strings.NewReader(payload), "application/json")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPPost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && err != tt.wantErrIs {
+				t.Errorf("HTTPPost() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if got := injector.Calls(apiSend) - before; got != tt.wantCalls {
+				t.Errorf("calls = %v, want %v", got, tt.wantCalls)
+			}
+			if got := injector.Pending(apiSend); got != 0 {
+				t.Errorf("Pending() = %v, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ScrubbedValue 录制时 敏感信息 被替换成的值
+const ScrubbedValue = "SCRUBBED"
+
+// SecretFields 录制时需要脱敏的 query 参数 / JSON 字段
+var SecretFields = []string{
+	"access_token",
+	"secret",
+	"appsecret",
+	"session_key",
+	"component_appsecret",
+	"component_access_token",
+	"authorizer_access_token",
+	"authorizer_refresh_token",
+}
+
+/*
+Fixture 一次录制的 请求/响应
+
+Body / Response 为原始文本，非 UTF-8 内容（如二维码图片）以 base64 保存，并通过 ResponseEncoding 标记
+*/
+type Fixture struct {
+	Method           string `json:"method"`
+	Path             string `json:"path"`
+	Query            string `json:"query,omitempty"`
+	Body             string `json:"body,omitempty"`
+	Status           int    `json:"status"`
+	ContentType      string `json:"content_type,omitempty"`
+	Response         string `json:"response"`
+	ResponseEncoding string `json:"response_encoding,omitempty"`
+}
+
+// ResponseBytes 还原 响应内容
+func (fixture Fixture) ResponseBytes() (resp []byte, err error) {
+	if fixture.ResponseEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(fixture.Response)
+	}
+	return []byte(fixture.Response), nil
+}
+
+// key 回放时 按 方法、路径、脱敏后的 query、归一化后的请求体 匹配
+func (fixture Fixture) key() string {
+	return fixtureKey(fixture.Method, fixture.Path, fixture.Query, []byte(fixture.Body))
+}
+
+// fixtureKey query 脱敏后 参数按名称排序，access_token 等变化 不影响匹配
+func fixtureKey(method string, path string, rawQuery string, body []byte) string {
+	return method + " " + path + "?" + ScrubQuery(rawQuery) + " " + NormalizeBody(body)
+}
+
+// LoadFixtures 从文件加载 录制的请求/响应
+func LoadFixtures(filename string) (fixtures []Fixture, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &fixtures)
+	return
+}
+
+// SaveFixtures 保存 录制的请求/响应 到文件
+func SaveFixtures(filename string, fixtures []Fixture) (err error) {
+	data, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+/*
+NormalizeBody 归一化请求体
+
+JSON 请求体 脱敏后按 key 排序输出，其他内容仅去除首尾空白，保证录制与回放时 access_token 等变化不影响匹配
+*/
+func NormalizeBody(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return string(body)
+	}
+
+	normalized, err := json.Marshal(scrubJSON(data))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(normalized)
+}
+
+// ScrubQuery query 参数脱敏
+func ScrubQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	for _, field := range SecretFields {
+		if _, ok := query[field]; ok {
+			query.Set(field, ScrubbedValue)
+		}
+	}
+
+	return query.Encode()
+}
+
+// ScrubBody JSON 内容脱敏，非 JSON 内容 原样返回
+func ScrubBody(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+
+	scrubbed, err := json.Marshal(scrubJSON(data))
+	if err != nil {
+		return body
+	}
+
+	return scrubbed
+}
+
+func scrubJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretField(key) {
+				v[key] = ScrubbedValue
+				continue
+			}
+			v[key] = scrubJSON(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = scrubJSON(value)
+		}
+	}
+	return data
+}
+
+func isSecretField(name string) bool {
+	for _, field := range SecretFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+FixtureRecorder 录制器
+
+作为代理 将请求转发到 Target（真实 api 服务器），并记录脱敏后的 请求/响应
+
+	recorder := test.NewFixtureRecorder(microapp.ServerUrl)
+	svr := httptest.NewServer(recorder)
+	microapp.ServerUrl = svr.URL
+	// ... 调用接口
+	_ = recorder.Save("testdata/fixtures.json")
+*/
+type FixtureRecorder struct {
+	Target string
+	Client *http.Client
+
+	mutex    sync.Mutex
+	fixtures []Fixture
+}
+
+// NewFixtureRecorder 创建 录制器
+func NewFixtureRecorder(target string) *FixtureRecorder {
+	return &FixtureRecorder{Target: strings.TrimRight(target, "/"), Client: http.DefaultClient}
+}
+
+// ServeHTTP 转发请求 并记录
+func (recorder *FixtureRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	req, err := http.NewRequest(r.Method, recorder.Target+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Del("Accept-Encoding") // 由 Transport 处理压缩，保证录制明文
+
+	response, err := recorder.Client.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+
+	resp, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	fixture := Fixture{
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       ScrubQuery(r.URL.RawQuery),
+		Body:        string(ScrubBody(body)),
+		Status:      response.StatusCode,
+		ContentType: response.Header.Get("Content-Type"),
+	}
+	if utf8.Valid(resp) {
+		fixture.Response = string(ScrubBody(resp))
+	} else {
+		fixture.Response = base64.StdEncoding.EncodeToString(resp)
+		fixture.ResponseEncoding = "base64"
+	}
+
+	recorder.mutex.Lock()
+	recorder.fixtures = append(recorder.fixtures, fixture)
+	recorder.mutex.Unlock()
+
+	for key, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+	_, _ = w.Write(resp)
+}
+
+// Fixtures 已录制的 请求/响应
+func (recorder *FixtureRecorder) Fixtures() []Fixture {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	fixtures := make([]Fixture, len(recorder.fixtures))
+	copy(fixtures, recorder.fixtures)
+	return fixtures
+}
+
+// Save 保存 已录制的 请求/响应 到文件
+func (recorder *FixtureRecorder) Save(filename string) error {
+	return SaveFixtures(filename, recorder.Fixtures())
+}
+
+/*
+FixtureReplayer 回放器
+
+按 方法、路径、脱敏后的 query、归一化后的请求体 匹配录制的响应；同一请求录制多次时 按顺序回放，最后一次响应重复使用
+*/
+type FixtureReplayer struct {
+	mutex    sync.Mutex
+	fixtures map[string][]Fixture
+	paths    []string
+}
+
+// NewFixtureReplayer 创建 回放器
+func NewFixtureReplayer(fixtures []Fixture) *FixtureReplayer {
+	replayer := &FixtureReplayer{fixtures: map[string][]Fixture{}}
+
+	seen := map[string]bool{}
+	for _, fixture := range fixtures {
+		key := fixture.key()
+		replayer.fixtures[key] = append(replayer.fixtures[key], fixture)
+
+		if !seen[fixture.Path] {
+			seen[fixture.Path] = true
+			replayer.paths = append(replayer.paths, fixture.Path)
+		}
+	}
+
+	return replayer
+}
+
+// Paths 录制过的 请求路径
+func (replayer *FixtureReplayer) Paths() []string {
+	return replayer.paths
+}
+
+// ServeHTTP 回放 匹配的响应，未匹配时 返回 404
+func (replayer *FixtureReplayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := fixtureKey(r.Method, r.URL.Path, r.URL.RawQuery, body)
+
+	replayer.mutex.Lock()
+	queue := replayer.fixtures[key]
+	if len(queue) == 0 {
+		replayer.mutex.Unlock()
+		http.Error(w, fmt.Sprintf(`{"errcode":404,"errmsg":"fixture not found: %s %s"}`, r.Method, r.URL.Path), http.StatusNotFound)
+		return
+	}
+	fixture := queue[0]
+	if len(queue) > 1 {
+		replayer.fixtures[key] = queue[1:]
+	}
+	replayer.mutex.Unlock()
+
+	resp, err := fixture.ResponseBytes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if fixture.ContentType != "" {
+		w.Header().Set("Content-Type", fixture.ContentType)
+	}
+	if fixture.Status != 0 {
+		w.WriteHeader(fixture.Status)
+	}
+	_, _ = w.Write(resp)
+}
+
+/*
+ReplayFixtures 从文件加载 录制的请求/响应，并在 mux 上注册回放
+
+mux 上已注册的路径（如 /api/apps/token）保持不变
+*/
+func ReplayFixtures(mux *http.ServeMux, filename string) (replayer *FixtureReplayer, err error) {
+	fixtures, err := LoadFixtures(filename)
+	if err != nil {
+		return
+	}
+
+	replayer = NewFixtureReplayer(fixtures)
+	for _, path := range replayer.Paths() {
+		if _, pattern := mux.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}}); pattern == path {
+			continue
+		}
+		mux.Handle(path, replayer)
+	}
+
+	return
+}
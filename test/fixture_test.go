@@ -0,0 +1,178 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "empty", body: "  ", want: ""},
+		{name: "sorted", body: `{"b":1,"a":{"d":2,"c":3}}`, want: `{"a":{"c":3,"d":2},"b":1}`},
+		{name: "scrubbed", body: `{"access_token":"abc","tasks":[{"content":"x"}]}`, want: `{"access_token":"SCRUBBED","tasks":[{"content":"x"}]}`},
+		{name: "not json", body: " key=value ", want: "key=value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("NormalizeBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrubQuery(t *testing.T) {
+	got := ScrubQuery("access_token=abc&openid=OPENID&secret=xyz")
+	want := "access_token=SCRUBBED&openid=OPENID&secret=SCRUBBED"
+	if got != want {
+		t.Errorf("ScrubQuery() = %v, want %v", got, want)
+	}
+}
+
+func TestFixtureRecordAndReplay(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0xff, 0xfe}
+
+	upstream := http.NewServeMux()
+	upstream.HandleFunc("/api/apps/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"REAL_TOKEN","expires_in":7200}`))
+	})
+	upstream.HandleFunc("/api/apps/versions", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"page":"` + r.URL.Query().Get("page") + `"}`))
+	})
+	upstream.HandleFunc("/api/apps/qrcode", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(png)
+	})
+	upstreamSvr := httptest.NewServer(upstream)
+	defer upstreamSvr.Close()
+
+	recorder := NewFixtureRecorder(upstreamSvr.URL)
+	recorderSvr := httptest.NewServer(recorder)
+	defer recorderSvr.Close()
+
+	resp, err := http.Get(recorderSvr.URL + "/api/apps/token?appid=APPID&secret=REAL_SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	for _, page := range []string{"1", "2"} {
+		resp, err = http.Get(recorderSvr.URL + "/api/apps/versions?access_token=REAL_TOKEN&page=" + page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	resp, err = http.Post(recorderSvr.URL+"/api/apps/qrcode", "application/json", strings.NewReader(`{"access_token":"REAL_TOKEN","path":"pages/index"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	filename := filepath.Join(t.TempDir(), "fixtures.json")
+	if err = recorder.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(saved, []byte("REAL_TOKEN")) || bytes.Contains(saved, []byte("REAL_SECRET")) {
+		t.Fatalf("fixtures not scrubbed: %s", saved)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/apps/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
+	})
+	replayer, err := ReplayFixtures(mux, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayer.Paths(), []string{"/api/apps/token", "/api/apps/versions", "/api/apps/qrcode"}) {
+		t.Errorf("Paths() = %v", replayer.Paths())
+	}
+
+	replaySvr := httptest.NewServer(mux)
+	defer replaySvr.Close()
+
+	// 只有 query 不同的 GET 请求 分别回放
+	queries := []struct {
+		query    string
+		wantCode int
+		wantResp string
+	}{
+		{query: "page=2&access_token=OTHER_TOKEN", wantCode: http.StatusOK, wantResp: `{"page":"2"}`},
+		{query: "access_token=OTHER_TOKEN&page=1", wantCode: http.StatusOK, wantResp: `{"page":"1"}`},
+		{query: "access_token=OTHER_TOKEN&page=3", wantCode: http.StatusNotFound},
+	}
+	for _, q := range queries {
+		resp, err := http.Get(replaySvr.URL + "/api/apps/versions?" + q.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotResp, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != q.wantCode || (q.wantResp != "" && string(gotResp) != q.wantResp) {
+			t.Errorf("GET versions?%s = %d %s, want %d %s", q.query, resp.StatusCode, gotResp, q.wantCode, q.wantResp)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantResp []byte
+	}{
+		{name: "token differs", body: `{"path":"pages/index","access_token":"OTHER_TOKEN"}`, wantCode: http.StatusOK, wantResp: png},
+		{name: "body differs", body: `{"access_token":"OTHER_TOKEN","path":"pages/other"}`, wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(replaySvr.URL+"/api/apps/qrcode", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("StatusCode = %v, want %v", resp.StatusCode, tt.wantCode)
+				return
+			}
+			if tt.wantResp == nil {
+				return
+			}
+			gotResp, _ := ioutil.ReadAll(resp.Body)
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("gotResp = %v, want %v", gotResp, tt.wantResp)
+			}
+			if resp.Header.Get("Content-Type") != "image/png" {
+				t.Errorf("Content-Type = %v", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+// MockErrcodeInvalidParam 模拟接口 请求方法、参数 或 access_token 不正确时 返回的错误码
+const MockErrcodeInvalidParam = 40014
+
+/*
+MockApi 模拟接口，由 cmd 根据接口定义 生成，见 mock_apis.go
+
+校验 请求方法、必填的 query 参数 和 access_token 的位置，通过后 返回符合响应结构的 示例数据 Response
+*/
+type MockApi struct {
+	Package        string
+	Name           string
+	Method         string
+	Path           string
+	ContentType    string // 请求体的 媒体类型，同一路径 有多种请求方式时 用于区分
+	Auth           string // query: access_token 参数 body: access_token 字段 header: X-Token 请求头 secret: appid/secret 参数
+	RequiredParams []string
+	Response       string
+}
+
+func (api MockApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != api.Method {
+		mockError(w, http.StatusMethodNotAllowed, "method %s not allowed, want %s", r.Method, api.Method)
+		return
+	}
+
+	query := r.URL.Query()
+	for _, name := range api.RequiredParams {
+		if query.Get(name) == "" {
+			mockError(w, http.StatusOK, "missing query param %s", name)
+			return
+		}
+	}
+
+	switch api.Auth {
+	case "header":
+		if r.Header.Get("X-Token") == "" {
+			mockError(w, http.StatusBadRequest, "missing X-Token header")
+			return
+		}
+	case "body":
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := struct {
+			AccessToken string `json:"access_token"`
+		}{}
+		if json.Unmarshal(body, &payload) != nil || payload.AccessToken == "" {
+			mockError(w, http.StatusOK, "missing access_token in body")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	_, _ = w.Write([]byte(api.Response))
+}
+
+func mockError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errcode": MockErrcodeInvalidParam, "errmsg": fmt.Sprintf(format, args...)})
+}
+
+// LookupMockApi 按路径 查找 模拟接口，同一路径 有多个接口时 返回第一个
+func LookupMockApi(path string) (api MockApi, ok bool) {
+	for _, api = range mockApis {
+		if api.Path == path {
+			return api, true
+		}
+	}
+	return MockApi{}, false
+}
+
+// mockApiSet 按路径 索引的 模拟接口
+type mockApiSet map[string][]MockApi
+
+/*
+lookup 按 路径 和 Content-Type 查找 模拟接口
+
+同一路径 只有一个接口时 不校验 Content-Type；有多个接口时（如 图片检测 的 JSON 和 文件上传）按 媒体类型 区分
+*/
+func (apis mockApiSet) lookup(r *http.Request) (api MockApi, ok bool) {
+	candidates := apis[r.URL.Path]
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	for _, api = range candidates {
+		if api.ContentType == mediaType {
+			return api, true
+		}
+	}
+	return MockApi{}, false
+}
+
+/*
+MockApiHandler 按 路径 和 Content-Type 分发到 模拟接口，未知路径 返回 404；指定 packages 时 只包含这些包的接口
+
+Setup 和 NewEnv 将其注册为 "/" 的处理器，在 mux 上单独注册的路径 优先
+*/
+func MockApiHandler(packages ...string) http.Handler {
+	selected := map[string]bool{}
+	for _, pkg := range packages {
+		selected[pkg] = true
+	}
+
+	apis := mockApiSet{}
+	for _, api := range mockApis {
+		if len(packages) == 0 || selected[api.Package] {
+			apis[api.Path] = append(apis[api.Path], api)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(apis[r.URL.Path]) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		api, ok := apis.lookup(r)
+		if !ok {
+			mockError(w, http.StatusUnsupportedMediaType, "content type %s not supported", r.Header.Get("Content-Type"))
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,302 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+// mockApis 根据 cmd/spec 中的接口定义 生成
+var mockApis = []MockApi{
+	{
+		Package:        "auth",
+		Name:           "code2Session",
+		Method:         "GET",
+		Path:           "/api/apps/jscode2session",
+		Auth:           "secret",
+		RequiredParams: []string{"appid", "secret"},
+		Response:       `{"errcode":0,"errmsg":"ok","session_key":"SESSION_KEY","openid":"OPENID","anonymous_openid":"ANONYMOUS_OPENID","unionid":"UNIONID"}`,
+	},
+	{
+		Package:        "code_management",
+		Name:           "上传代码",
+		Method:         "POST",
+		Path:           "/openapi/v1/microapp/package/upload",
+		ContentType:    "application/json",
+		RequiredParams: []string{"component_appid", "authorizer_access_token"},
+		Response:       `{"errno":0,"message":"MESSAGE"}`,
+	},
+	{
+		Package:        "code_management",
+		Name:           "提审代码",
+		Method:         "POST",
+		Path:           "/openapi/v1/microapp/package/audit",
+		ContentType:    "application/json",
+		RequiredParams: []string{"component_appid", "authorizer_access_token"},
+		Response:       `{"errno":0,"message":"MESSAGE"}`,
+	},
+	{
+		Package:        "code_management",
+		Name:           "查询版本",
+		Method:         "GET",
+		Path:           "/openapi/v1/microapp/package/versions",
+		RequiredParams: []string{"component_appid", "authorizer_access_token"},
+		Response:       `{"errno":0,"message":"MESSAGE","data":{"current":{"version":"VERSION","summary":"SUMMARY","developer_avatar":"DEVELOPER_AVATAR","developer_id":"DEVELOPER_ID","developer_name":"DEVELOPER_NAME","ctime":0,"has_down":false},"audit":{"version":"VERSION","summary":"SUMMARY","developer_avatar":"DEVELOPER_AVATAR","developer_id":"DEVELOPER_ID","developer_name":"DEVELOPER_NAME","ctime":0,"status":0,"reason":"REASON","has_publish":false},"latest":{"version":"VERSION","summary":"SUMMARY","developer_avatar":"DEVELOPER_AVATAR","developer_id":"DEVELOPER_ID","developer_name":"DEVELOPER_NAME","ctime":0}}}`,
+	},
+	{
+		Package:        "code_management",
+		Name:           "发布代码",
+		Method:         "POST",
+		Path:           "/openapi/v1/microapp/package/release",
+		ContentType:    "application/json",
+		RequiredParams: []string{"component_appid", "authorizer_access_token"},
+		Response:       `{"errno":0,"message":"MESSAGE"}`,
+	},
+	{
+		Package:        "code_management",
+		Name:           "回退代码版本",
+		Method:         "POST",
+		Path:           "/openapi/v1/microapp/package/rollback",
+		ContentType:    "application/json",
+		RequiredParams: []string{"component_appid", "authorizer_access_token"},
+		Response:       `{"errno":0,"message":"MESSAGE"}`,
+	},
+	{
+		Package:     "content_security",
+		Name:        "内容安全检测",
+		Method:      "POST",
+		Path:        "/api/v2/tags/text/antidirt",
+		ContentType: "application/json",
+		Auth:        "header",
+		Response:    `{"log_id":"LOG_ID","data":[{"msg":"MSG","code":0,"task_id":"TASK_ID","predicts":[{"prob":0,"model_name":"MODEL_NAME","target":"TARGET"}],"data_id":"DATA_ID"}]}`,
+	},
+	{
+		Package:     "content_security",
+		Name:        "图片检测",
+		Method:      "POST",
+		Path:        "/api/v2/tags/image/",
+		ContentType: "application/json",
+		Auth:        "header",
+		Response:    `{"log_id":"LOG_ID","data":[{"msg":"MSG","code":0,"task_id":"TASK_ID","predicts":[{"model_name":"MODEL_NAME","hit":false}],"data_id":"DATA_ID","cached":false}]}`,
+	},
+	{
+		Package:     "content_security",
+		Name:        "图片检测（文件上传）",
+		Method:      "POST",
+		Path:        "/api/v2/tags/image/",
+		ContentType: "multipart/form-data",
+		Auth:        "header",
+		Response:    `{"log_id":"LOG_ID","data":[{"msg":"MSG","code":0,"task_id":"TASK_ID","predicts":[{"model_name":"MODEL_NAME","hit":false}],"data_id":"DATA_ID","cached":false}]}`,
+	},
+	{
+		Package:        "data_caching",
+		Name:           "setUserStorage",
+		Method:         "POST",
+		Path:           "/api/apps/set_user_storage",
+		ContentType:    "application/json",
+		Auth:           "query",
+		RequiredParams: []string{"access_token", "openid", "signature", "sig_method"},
+		Response:       `{"errcode":0,"errmsg":"ok"}`,
+	},
+	{
+		Package:        "data_caching",
+		Name:           "removeUserStorage",
+		Method:         "POST",
+		Path:           "/api/apps/remove_user_storage",
+		ContentType:    "application/json",
+		Auth:           "query",
+		RequiredParams: []string{"access_token", "openid", "signature", "sig_method"},
+		Response:       `{"errcode":0,"errmsg":"ok"}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "预下单",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/create_order",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","data":{"order_id":"ORDER_ID","order_token":"ORDER_TOKEN"}}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "查询订单",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/query_order",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","out_order_no":"OUT_ORDER_NO","order_id":"ORDER_ID","payment_info":{"total_fee":0,"order_status":"ORDER_STATUS","pay_time":"PAY_TIME","way":0,"channel_no":"CHANNEL_NO","channel_gateway_no":"CHANNEL_GATEWAY_NO","seller_uid":"SELLER_UID","item_id":"ITEM_ID"}}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "退款",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/create_refund",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","refund_no":"REFUND_NO"}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "查询退款",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/query_refund",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","refundInfo":{"refund_no":"REFUND_NO","refund_amount":0,"refund_status":"REFUND_STATUS","refunded_at":0,"is_all_settled":false,"cp_extra":"CP_EXTRA"}}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "结算及分账",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/settle",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","settle_no":"SETTLE_NO"}`,
+	},
+	{
+		Package:     "ecpay",
+		Name:        "查询结算",
+		Method:      "POST",
+		Path:        "/api/apps/ecpay/v1/query_settle",
+		ContentType: "application/json",
+		Response:    `{"err_no":0,"err_tips":"ok","settle_info":{"settle_no":"SETTLE_NO","settle_amount":0,"settle_status":"SETTLE_STATUS","settled_at":0,"rake":0,"commission":0,"cp_extra":"CP_EXTRA"}}`,
+	},
+	{
+		Package:     "order",
+		Name:        "订单同步",
+		Method:      "POST",
+		Path:        "/api/apps/order/v2/push",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_code":0,"err_msg":"ERR_MSG","body":"BODY"}`,
+	},
+	{
+		Package:     "qrcode",
+		Name:        "createQRCode",
+		Method:      "POST",
+		Path:        "/api/apps/qrcode",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"errcode":0,"errmsg":"ok"}`,
+	},
+	{
+		Package:     "share",
+		Name:        "查询分享模板列表",
+		Method:      "POST",
+		Path:        "/api/apps/share/template/list",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","data":{"total":0,"templates":[{"template_id":"TEMPLATE_ID","title":"TITLE","desc":"DESC","image_url":"IMAGE_URL","path":"PATH","query":"QUERY","status":0,"create_time":0}]}}`,
+	},
+	{
+		Package:     "share",
+		Name:        "创建分享模板",
+		Method:      "POST",
+		Path:        "/api/apps/share/template/create",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","data":{"template_id":"TEMPLATE_ID"}}`,
+	},
+	{
+		Package:     "share",
+		Name:        "查询分享数据",
+		Method:      "POST",
+		Path:        "/api/apps/share/data",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","data":{"list":[{"date":"DATE","share_count":0,"share_user_count":0,"click_count":0,"click_user_count":0}]}}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "订阅消息推送",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/notify",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok"}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "查询模板库",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/template/library/list",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","total":0,"template_list":[{"title_id":"TITLE_ID","title":"TITLE","type":0}]}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "查询模板库关键词",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/template/library/keywords",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","keyword_list":[{"kid":"KID","name":"NAME","example":"EXAMPLE","rule":"RULE"}]}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "查询小程序模板",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/template/list",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","total":0,"template_list":[{"tpl_id":"TPL_ID","title_id":"TITLE_ID","title":"TITLE","type":0,"keyword_list":[{"kid":"KID","name":"NAME","example":"EXAMPLE","rule":"RULE"}]}]}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "创建小程序模板",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/template/create",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","tpl_id":"TPL_ID"}`,
+	},
+	{
+		Package:     "subscribe_notification",
+		Name:        "删除小程序模板",
+		Method:      "POST",
+		Path:        "/api/apps/subscribe_notification/developer/v1/template/delete",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok"}`,
+	},
+	{
+		Package:     "template_message",
+		Name:        "发送模版消息",
+		Method:      "POST",
+		Path:        "/api/apps/game/template/send",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"errcode":0,"errmsg":"ok"}`,
+	},
+	{
+		Package:     "url_link",
+		Name:        "生成 URL Link",
+		Method:      "POST",
+		Path:        "/api/apps/url_link/generate",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","url_link":"URL_LINK"}`,
+	},
+	{
+		Package:     "url_link",
+		Name:        "查询 URL Link",
+		Method:      "POST",
+		Path:        "/api/apps/url_link/query_info",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","url_link_info":{"ma_app_id":"MA_APP_ID","app_name":"APP_NAME","path":"PATH","query":"QUERY","create_time":0,"expire_time":0}}`,
+	},
+	{
+		Package:     "url_link",
+		Name:        "查询 URL Link 配额",
+		Method:      "POST",
+		Path:        "/api/apps/url_link/query_quota",
+		ContentType: "application/json",
+		Auth:        "body",
+		Response:    `{"err_no":0,"err_tips":"ok","url_link_quota":{"url_link_used":0,"url_link_limit":0}}`,
+	},
+}
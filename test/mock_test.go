@@ -0,0 +1,127 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMockApi(t *testing.T) {
+	handler := MockApiHandler()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		header     http.Header
+		body       string
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "ok", method: http.MethodPost, target: "/api/apps/set_user_storage?access_token=ACCESS_TOKEN&openid=OPENID&signature=SIG&sig_method=hmac_sha256", wantStatus: http.StatusOK},
+		{name: "method", method: http.MethodGet, target: "/api/apps/set_user_storage", wantStatus: http.StatusMethodNotAllowed, wantErr: true},
+		{name: "query access_token", method: http.MethodPost, target: "/api/apps/set_user_storage?openid=OPENID&signature=SIG&sig_method=hmac_sha256", wantStatus: http.StatusOK, wantErr: true},
+		{name: "required param", method: http.MethodPost, target: "/api/apps/set_user_storage?access_token=ACCESS_TOKEN&openid=OPENID", wantStatus: http.StatusOK, wantErr: true},
+		{name: "body access_token", method: http.MethodPost, target: "/api/apps/qrcode", body: `{"access_token":"ACCESS_TOKEN"}`, wantStatus: http.StatusOK},
+		{name: "no body access_token", method: http.MethodPost, target: "/api/apps/qrcode", body: `{"path":"pages/index"}`, wantStatus: http.StatusOK, wantErr: true},
+		{name: "X-Token", method: http.MethodPost, target: "/api/v2/tags/text/antidirt", header: http.Header{"X-Token": {"ACCESS_TOKEN"}}, body: `{}`, wantStatus: http.StatusOK},
+		{name: "no X-Token", method: http.MethodPost, target: "/api/v2/tags/text/antidirt", body: `{}`, wantStatus: http.StatusBadRequest, wantErr: true},
+		{name: "secret", method: http.MethodGet, target: "/api/apps/jscode2session?appid=APPID&secret=SECRET&code=CODE", wantStatus: http.StatusOK},
+		{name: "no secret", method: http.MethodGet, target: "/api/apps/jscode2session?appid=APPID&code=CODE", wantStatus: http.StatusOK, wantErr: true},
+		{name: "unknown", method: http.MethodGet, target: "/api/apps/unknown", wantStatus: http.StatusNotFound, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for key, values := range tt.header {
+				r.Header[key] = values
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			resp := struct {
+				Errcode int `json:"errcode"`
+			}{}
+			gotErr := json.Unmarshal(w.Body.Bytes(), &resp) != nil || resp.Errcode != 0
+			if gotErr != tt.wantErr {
+				t.Errorf("response = %s, wantErr %v", w.Body.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMockApiResponse(t *testing.T) {
+	for _, api := range mockApis {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(api.Response), &data); err != nil {
+			t.Errorf("%s %s response: %v", api.Package, api.Path, err)
+		}
+	}
+
+	api, ok := LookupMockApi("/api/apps/jscode2session")
+	if !ok || !strings.Contains(api.Response, `"openid":"OPENID"`) {
+		t.Errorf("LookupMockApi() = %+v, %v", api, ok)
+	}
+	if _, ok = LookupMockApi("/api/apps/unknown"); ok {
+		t.Error("LookupMockApi() found unknown path")
+	}
+}
+
+func TestMockApiHandlerPackages(t *testing.T) {
+	handler := MockApiHandler("qrcode")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/apps/jscode2session?appid=APPID&secret=SECRET", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 for api outside selected packages", w.Code)
+	}
+}
+
+func TestMockApiSetLookup(t *testing.T) {
+	apis := mockApiSet{}
+	for _, api := range mockApis {
+		apis[api.Path] = append(apis[api.Path], api)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		want        string
+		wantOk      bool
+	}{
+		{name: "json", path: "/api/v2/tags/image/", contentType: "application/json;charset=utf-8", want: "图片检测", wantOk: true},
+		{name: "upload", path: "/api/v2/tags/image/", contentType: "multipart/form-data; boundary=b", want: "图片检测（文件上传）", wantOk: true},
+		{name: "unknown content type", path: "/api/v2/tags/image/", contentType: "text/plain"},
+		{name: "single api", path: "/api/apps/qrcode", want: "createQRCode", wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			r.Header.Set("Content-Type", tt.contentType)
+			api, ok := apis.lookup(r)
+			if ok != tt.wantOk || api.Name != tt.want {
+				t.Errorf("lookup() = %s, %v, want %s, %v", api.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+FakePlatform 有状态的 字节小程序 模拟服务器
+
+- 颁发轮换的 access_token（ACCESS_TOKEN_1、ACCESS_TOKEN_2 ...），超过 TokenTTL 后失效，携带失效 token 的请求返回 40002（X-Token 鉴权的接口返回 401）
+
+- 通过 Busy 模拟连续的 errcode -1 系统繁忙
+
+- 实现 code2Session、数据缓存、二维码、内容安全、模板消息、订阅消息 等接口的真实行为
+
+	platform := test.NewFakePlatform("APPID", "SECRET")
+	svr := httptest.NewServer(platform)
+	microapp.ServerUrl = svr.URL
+*/
+type FakePlatform struct {
+	AppId     string
+	AppSecret string
+	TokenTTL  time.Duration
+	Now       func() time.Time // 当前时间，可替换以模拟 token 过期
+
+	BadWords  []string // 命中即判定为违规的文本
+	BadImages []string // 图片地址/数据 包含即判定为违规
+
+	mux     *http.ServeMux
+	mutex   sync.Mutex
+	seq     int
+	tokens  map[string]time.Time // access_token -> 过期时间
+	busy    int
+	calls   map[string]int
+	storage map[string]map[string]string // openid -> key/value
+	session map[string]string            // openid -> session_key
+}
+
+// NewFakePlatform 创建 模拟服务器
+func NewFakePlatform(appid, secret string) *FakePlatform {
+	platform := &FakePlatform{
+		AppId:     appid,
+		AppSecret: secret,
+		TokenTTL:  2 * time.Hour,
+		Now:       time.Now,
+		BadWords:  []string{"违规"},
+		BadImages: []string{"bad"},
+		tokens:    map[string]time.Time{},
+		calls:     map[string]int{},
+		storage:   map[string]map[string]string{},
+		session:   map[string]string{},
+	}
+
+	platform.mux = http.NewServeMux()
+	platform.Register(platform.mux)
+
+	return platform
+}
+
+// ServeHTTP 实现 http.Handler
+func (platform *FakePlatform) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	platform.mux.ServeHTTP(w, r)
+}
+
+// Register 在 mux 上注册 模拟接口
+func (platform *FakePlatform) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/api/apps/token", platform.handleToken)
+	mux.HandleFunc("/api/apps/jscode2session", platform.handleCode2Session)
+	mux.HandleFunc("/api/apps/set_user_storage", platform.withToken(tokenInQuery, platform.handleSetUserStorage))
+	mux.HandleFunc("/api/apps/remove_user_storage", platform.withToken(tokenInQuery, platform.handleRemoveUserStorage))
+	mux.HandleFunc("/api/apps/qrcode", platform.withToken(tokenInBody, platform.handleQRCode))
+	mux.HandleFunc("/api/apps/game/template/send", platform.withToken(tokenInBody, platform.handleOK))
+	mux.HandleFunc("/api/apps/subscribe_notification/developer/v1/notify", platform.withToken(tokenInBody, platform.handleNotify))
+	mux.HandleFunc("/api/v2/tags/text/antidirt", platform.withToken(tokenInHeader, platform.handleTextAntiDirty))
+	mux.HandleFunc("/api/v2/tags/image/", platform.withToken(tokenInHeader, platform.handleImage))
+}
+
+// Busy 接下来 n 次接口调用（不含获取 access_token）返回 errcode -1
+func (platform *FakePlatform) Busy(n int) {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	platform.busy = n
+}
+
+// ExpireTokens 使已颁发的 access_token 全部失效
+func (platform *FakePlatform) ExpireTokens() {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	platform.tokens = map[string]time.Time{}
+}
+
+// CurrentToken 最近颁发的 access_token
+func (platform *FakePlatform) CurrentToken() string {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	return fmt.Sprintf("ACCESS_TOKEN_%d", platform.seq)
+}
+
+// Calls 路径 path 被调用的次数
+func (platform *FakePlatform) Calls(path string) int {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	return platform.calls[path]
+}
+
+// Storage 用户 openid 的云存储数据
+func (platform *FakePlatform) Storage(openid string) map[string]string {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	kv := map[string]string{}
+	for key, value := range platform.storage[openid] {
+		kv[key] = value
+	}
+	return kv
+}
+
+// SessionKey 用户 openid 的 session_key，code2Session 后可用
+func (platform *FakePlatform) SessionKey(openid string) string {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	return platform.session[openid]
+}
+
+const (
+	tokenInQuery = iota
+	tokenInBody
+	tokenInHeader
+)
+
+// withToken 校验 access_token 并模拟 系统繁忙
+func (platform *FakePlatform) withToken(placement int, next func(w http.ResponseWriter, r *http.Request, body []byte)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		platform.mutex.Lock()
+		platform.calls[r.URL.Path]++
+		busy := platform.busy > 0
+		if busy {
+			platform.busy--
+		}
+		platform.mutex.Unlock()
+
+		if busy {
+			writeJSON(w, map[string]interface{}{"errcode": -1, "errmsg": "system busy"})
+			return
+		}
+
+		var accessToken string
+		switch placement {
+		case tokenInQuery:
+			accessToken = r.URL.Query().Get("access_token")
+		case tokenInHeader:
+			accessToken = r.Header.Get("X-Token")
+		case tokenInBody:
+			payload := struct {
+				AccessToken string `json:"access_token"`
+			}{}
+			_ = json.Unmarshal(body, &payload)
+			accessToken = payload.AccessToken
+		}
+
+		if !platform.validToken(accessToken) {
+			if placement == tokenInHeader {
+				http.Error(w, `{"error_id":"401","message":"bad access_token"}`, http.StatusUnauthorized)
+				return
+			}
+			writeJSON(w, map[string]interface{}{"errcode": 40002, "errmsg": "bad access_token"})
+			return
+		}
+
+		next(w, r, body)
+	}
+}
+
+func (platform *FakePlatform) validToken(accessToken string) bool {
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	expireAt, ok := platform.tokens[accessToken]
+	return ok && platform.Now().Before(expireAt)
+}
+
+func (platform *FakePlatform) handleToken(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	platform.calls[r.URL.Path]++
+
+	if query.Get("appid") != platform.AppId || query.Get("secret") != platform.AppSecret || query.Get("grant_type") != "client_credential" {
+		writeJSON(w, map[string]interface{}{"errcode": 40015, "errmsg": "bad appid or secret"})
+		return
+	}
+
+	platform.seq++
+	accessToken := fmt.Sprintf("ACCESS_TOKEN_%d", platform.seq)
+	platform.tokens[accessToken] = platform.Now().Add(platform.TokenTTL)
+
+	writeJSON(w, map[string]interface{}{"access_token": accessToken, "expires_in": int(platform.TokenTTL / time.Second)})
+}
+
+func (platform *FakePlatform) handleCode2Session(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	platform.calls[r.URL.Path]++
+
+	if query.Get("appid") != platform.AppId || query.Get("secret") != platform.AppSecret {
+		writeJSON(w, map[string]interface{}{"errcode": 40015, "errmsg": "bad appid or secret"})
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		code = query.Get("anonymous_code")
+	}
+	if code == "" {
+		writeJSON(w, map[string]interface{}{"errcode": 40014, "errmsg": "bad code"})
+		return
+	}
+
+	openid := "OPENID_" + code
+	sessionKey := "SESSION_KEY_" + code
+	platform.session[openid] = sessionKey
+
+	writeJSON(w, map[string]interface{}{"errcode": 0, "errmsg": "", "openid": openid, "session_key": sessionKey})
+}
+
+// checkSignature 校验 数据缓存接口 的用户登录态签名 hmac_sha256(session_key, body)
+func (platform *FakePlatform) checkSignature(r *http.Request, body []byte) (openid string, ok bool) {
+	query := r.URL.Query()
+	openid = query.Get("openid")
+
+	platform.mutex.Lock()
+	sessionKey := platform.session[openid]
+	platform.mutex.Unlock()
+
+	// 未调用过 code2Session 的用户 没有 session_key，签名 一律无效
+	if openid == "" || sessionKey == "" || query.Get("sig_method") != "hmac_sha256" {
+		return openid, false
+	}
+
+	mac := hmac.New(sha256.New, []byte(sessionKey))
+	_, _ = mac.Write(body)
+	return openid, query.Get("signature") == hex.EncodeToString(mac.Sum(nil))
+}
+
+func (platform *FakePlatform) handleSetUserStorage(w http.ResponseWriter, r *http.Request, body []byte) {
+	openid, ok := platform.checkSignature(r, body)
+	if !ok {
+		writeJSON(w, map[string]interface{}{"errcode": 60001, "errmsg": "bad signature"})
+		return
+	}
+
+	payload := struct {
+		KvList []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kv_list"`
+	}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeJSON(w, map[string]interface{}{"errcode": 40001, "errmsg": "bad params"})
+		return
+	}
+
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	if platform.storage[openid] == nil {
+		platform.storage[openid] = map[string]string{}
+	}
+	for _, kv := range payload.KvList {
+		platform.storage[openid][kv.Key] = kv.Value
+	}
+
+	writeJSON(w, map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+}
+
+func (platform *FakePlatform) handleRemoveUserStorage(w http.ResponseWriter, r *http.Request, body []byte) {
+	openid, ok := platform.checkSignature(r, body)
+	if !ok {
+		writeJSON(w, map[string]interface{}{"errcode": 60001, "errmsg": "bad signature"})
+		return
+	}
+
+	payload := struct {
+		Key []string `json:"key"`
+	}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeJSON(w, map[string]interface{}{"errcode": 40001, "errmsg": "bad params"})
+		return
+	}
+
+	platform.mutex.Lock()
+	defer platform.mutex.Unlock()
+
+	for _, key := range payload.Key {
+		delete(platform.storage[openid], key)
+	}
+
+	writeJSON(w, map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+}
+
+func (platform *FakePlatform) handleQRCode(w http.ResponseWriter, r *http.Request, body []byte) {
+	payload := struct {
+		Width int `json:"width"`
+	}{}
+	_ = json.Unmarshal(body, &payload)
+	if payload.Width <= 0 {
+		payload.Width = 430
+	}
+
+	img := image.NewGray(image.Rect(0, 0, payload.Width, payload.Width))
+	for i := 0; i < payload.Width; i++ {
+		img.SetGray(i, i, color.Gray{Y: 255})
+	}
+
+	buf := bytes.Buffer{}
+	_ = png.Encode(&buf, img)
+
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (platform *FakePlatform) handleOK(w http.ResponseWriter, r *http.Request, body []byte) {
+	writeJSON(w, map[string]interface{}{"errcode": 0, "errmsg": "ok"})
+}
+
+func (platform *FakePlatform) handleNotify(w http.ResponseWriter, r *http.Request, body []byte) {
+	payload := struct {
+		AppId  string `json:"app_id"`
+		TplId  string `json:"tpl_id"`
+		OpenId string `json:"open_id"`
+	}{}
+	_ = json.Unmarshal(body, &payload)
+
+	if payload.AppId != platform.AppId || payload.TplId == "" || payload.OpenId == "" {
+		writeJSON(w, map[string]interface{}{"err_no": 1, "err_tips": "bad params"})
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"err_no": 0, "err_tips": ""})
+}
+
+func (platform *FakePlatform) handleTextAntiDirty(w http.ResponseWriter, r *http.Request, body []byte) {
+	payload := struct {
+		Tasks []struct {
+			Content string `json:"content"`
+		} `json:"tasks"`
+	}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, `{"error_id":"400","message":"bad request"}`, http.StatusBadRequest)
+		return
+	}
+
+	var data []map[string]interface{}
+	for i, task := range payload.Tasks {
+		prob := 0
+		for _, word := range platform.BadWords {
+			if strings.Contains(task.Content, word) {
+				prob = 1
+			}
+		}
+		data = append(data, map[string]interface{}{
+			"msg":     "",
+			"code":    0,
+			"task_id": fmt.Sprintf("TASK_%d", i),
+			"predicts": []map[string]interface{}{
+				{"prob": prob, "model_name": "short_content_antidirt"},
+			},
+			"data_id": nil,
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{"log_id": "LOG_ID", "data": data})
+}
+
+func (platform *FakePlatform) handleImage(w http.ResponseWriter, r *http.Request, body []byte) {
+	payload := struct {
+		Targets []string `json:"targets"`
+		Tasks   []struct {
+			Image     string `json:"image"`
+			ImageData string `json:"image_data"`
+		} `json:"tasks"`
+	}{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, `{"error_id":"400","message":"bad request"}`, http.StatusBadRequest)
+		return
+	}
+	if len(payload.Targets) == 0 {
+		payload.Targets = []string{"ad", "porn", "politics", "disgusting"}
+	}
+
+	var data []map[string]interface{}
+	for i, task := range payload.Tasks {
+		hit := false
+		for _, bad := range platform.BadImages {
+			if strings.Contains(task.Image+task.ImageData, bad) {
+				hit = true
+			}
+		}
+
+		var predicts []map[string]interface{}
+		for _, target := range payload.Targets {
+			predicts = append(predicts, map[string]interface{}{"model_name": target, "hit": hit})
+		}
+
+		data = append(data, map[string]interface{}{
+			"msg":      "",
+			"code":     0,
+			"task_id":  fmt.Sprintf("TASK_%d", i),
+			"predicts": predicts,
+			"data_id":  nil,
+			"cached":   false,
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{"log_id": "LOG_ID", "data": data})
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego/sync"
+	"github.com/fastwego/microapp"
+)
+
+// newPlatformApp 创建 指向 模拟服务器 的小程序实例
+func newPlatformApp(t *testing.T) (*FakePlatform, *microapp.MicroApp) {
+	platform := NewFakePlatform("APPID", "SECRET")
+	svr := httptest.NewServer(platform)
+
+	serverUrl := microapp.ServerUrl
+	microapp.ServerUrl = svr.URL
+	t.Cleanup(func() {
+		microapp.ServerUrl = serverUrl
+		svr.Close()
+	})
+
+	app := microapp.New(microapp.Config{AppId: "APPID", AppSecret: "SECRET"})
+	app.Cache = sync.New()
+	app.Logger = nil
+
+	return platform, app
+}
+
+func TestFakePlatformTokenRotation(t *testing.T) {
+	platform, app := newPlatformApp(t)
+
+	now := time.Now()
+	platform.Now = func() time.Time { return now }
+
+	payload := []byte(`{"tasks":[{"content":"hello"}]}`)
+	req, _ := http.NewRequest(http.MethodPost, microapp.ServerUrl+"/api/v2/tags/text/antidirt", bytes.NewReader(payload))
+	accessToken, err := app.GetAccessTokenHandler(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accessToken != "ACCESS_TOKEN_1" {
+		t.Fatalf("accessToken = %v", accessToken)
+	}
+	req.Header.Set("X-Token", accessToken)
+	if _, err = app.Client.HTTPDo(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = app.Client.HTTPGet("/api/apps/jscode2session?appid=APPID&secret=SECRET&code=CODE"); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"kv_list":[{"key":"k","value":"v"}]}`
+	mac := hmac.New(sha256.New, []byte(platform.SessionKey("OPENID_CODE")))
+	_, _ = mac.Write([]byte(body))
+
+	// token 在服务端过期，但本地缓存仍然有效：401 -> 刷新 -> 重试
+	now = now.Add(3 * time.Hour)
+
+	params := url.Values{}
+	params.Add("access_token", accessToken)
+	params.Add("openid", "OPENID_CODE")
+	params.Add("signature", hex.EncodeToString(mac.Sum(nil)))
+	params.Add("sig_method", "hmac_sha256")
+	_, err = app.Client.HTTPPost("/api/apps/set_user_storage?"+params.Encode(), strings.NewReader(body), "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := platform.Calls("/api/apps/token"); got != 2 {
+		t.Errorf("token calls = %v, want 2", got)
+	}
+	if got := platform.Calls("/api/apps/set_user_storage"); got != 2 {
+		t.Errorf("set_user_storage calls = %v, want 2", got)
+	}
+	if got := platform.CurrentToken(); got != "ACCESS_TOKEN_2" {
+		t.Errorf("CurrentToken() = %v", got)
+	}
+	if got := platform.Storage("OPENID_CODE")["k"]; got != "v" {
+		t.Errorf("Storage() = %v", got)
+	}
+}
+
+func TestFakePlatformBusy(t *testing.T) {
+	platform, app := newPlatformApp(t)
+
+	tests := []struct {
+		name    string
+		busy    int
+		wantErr error
+	}{
+		{name: "retry", busy: 1, wantErr: nil},
+		{name: "burst", busy: 2, wantErr: microapp.ErrorSystemBusy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessToken, err := app.GetAccessTokenHandler(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			platform.Busy(tt.busy)
+			payload := `{"access_token":"` + accessToken + `","touser":"OPENID","template_id":"TPL"}`
+			_, err = app.Client.HTTPPost("/api/apps/game/template/send", strings.NewReader(payload), "application/json")
+			if err != tt.wantErr {
+				t.Errorf("HTTPPost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFakePlatformUserStorage(t *testing.T) {
+	platform, app := newPlatformApp(t)
+
+	resp, err := app.Client.HTTPGet("/api/apps/jscode2session?appid=APPID&secret=SECRET&code=CODE")
+	if err != nil {
+		t.Fatalf("%v %s", err, resp)
+	}
+	openid := "OPENID_CODE"
+	sessionKey := platform.SessionKey(openid)
+
+	accessToken, err := app.GetAccessTokenHandler(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(uri string, body string, sessionKey string) error {
+		mac := hmac.New(sha256.New, []byte(sessionKey))
+		_, _ = mac.Write([]byte(body))
+
+		params := url.Values{}
+		params.Add("access_token", accessToken)
+		params.Add("openid", openid)
+		params.Add("signature", hex.EncodeToString(mac.Sum(nil)))
+		params.Add("sig_method", "hmac_sha256")
+		_, err := app.Client.HTTPPost(uri+"?"+params.Encode(), strings.NewReader(body), "application/json")
+		return err
+	}
+
+	if err = call("/api/apps/set_user_storage", `{"kv_list":[{"key":"a","value":"1"},{"key":"b","value":"2"}]}`, sessionKey); err != nil {
+		t.Fatal(err)
+	}
+	if err = call("/api/apps/remove_user_storage", `{"key":["a"]}`, sessionKey); err != nil {
+		t.Fatal(err)
+	}
+	if err = call("/api/apps/set_user_storage", `{"kv_list":[{"key":"c","value":"3"}]}`, "WRONG_KEY"); err == nil {
+		t.Error("bad signature accepted")
+	}
+
+	got := platform.Storage(openid)
+	if len(got) != 1 || got["b"] != "2" {
+		t.Errorf("Storage() = %v", got)
+	}
+}
+
+func TestFakePlatformQRCode(t *testing.T) {
+	_, app := newPlatformApp(t)
+
+	accessToken, err := app.GetAccessTokenHandler(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := app.Client.HTTPPost("/api/apps/qrcode", strings.NewReader(`{"access_token":"`+accessToken+`","width":64}`), "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(resp, []byte("\x89PNG")) {
+		t.Errorf("resp is not png: %v", resp[:8])
+	}
+}
+
+func TestFakePlatformContentSecurity(t *testing.T) {
+	_, app := newPlatformApp(t)
+
+	accessToken, err := app.GetAccessTokenHandler(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		uri      string
+		payload  string
+		wantResp string
+	}{
+		{name: "text pass", uri: "/api/v2/tags/text/antidirt", payload: `{"tasks":[{"content":"你好"}]}`, wantResp: `"prob":0`},
+		{name: "text reject", uri: "/api/v2/tags/text/antidirt", payload: `{"tasks":[{"content":"违规内容"}]}`, wantResp: `"prob":1`},
+		{name: "image pass", uri: "/api/v2/tags/image/", payload: `{"targets":["porn"],"tasks":[{"image":"https://example.com/ok.png"}]}`, wantResp: `"hit":false`},
+		{name: "image reject", uri: "/api/v2/tags/image/", payload: `{"targets":["porn"],"tasks":[{"image":"https://example.com/bad.png"}]}`, wantResp: `"hit":true`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, microapp.ServerUrl+tt.uri, strings.NewReader(tt.payload))
+			req.Header.Set("X-Token", accessToken)
+
+			resp, err := app.Client.HTTPDo(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(resp), tt.wantResp) {
+				t.Errorf("resp = %s, want %s", resp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -26,9 +26,15 @@ import (
 var MockMicroApp *microapp.MicroApp
 var MockSvr *httptest.Server
 var MockSvrHandler *http.ServeMux
+var MockFaults *FaultInjector
+var MockCapture *RequestCapture
 var onceSetup sync.Once
 
-// 初始化测试环境
+/*
+初始化测试环境
+
+所有测试共享 全局的 模拟服务器 和 小程序实例；需要并行或独立配置时 使用 NewEnv
+*/
 func Setup() {
 	onceSetup.Do(func() {
 
@@ -39,12 +45,15 @@ func Setup() {
 
 		// Mock Server
 		MockSvrHandler = http.NewServeMux()
-		MockSvr = httptest.NewServer(MockSvrHandler)
+		MockFaults = NewFaultInjector(MockSvrHandler)
+		MockCapture = NewRequestCapture(MockFaults)
+		MockSvr = httptest.NewServer(MockCapture)
 		microapp.ServerUrl = MockSvr.URL // 拦截发往服务器的请求
 
 		// Mock access token
-		MockSvrHandler.HandleFunc("/api/apps/token", func(w http.ResponseWriter, r *http.Request) {
-			_, _ = w.Write([]byte(`{"access_token":"ACCESS_TOKEN","expires_in":7200}`))
-		})
+		MockSvrHandler.HandleFunc("/api/apps/token", mockAccessToken)
+
+		// 未单独注册的路径 由 模拟接口 处理
+		MockSvrHandler.Handle("/", MockApiHandler())
 	})
 }